@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/entity"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// addEntityCmd represents the add:entity command
+var addEntityCmd = &cobra.Command{
+	Use:   "add:entity <Name>",
+	Short: "Generate a domain entity with its repository, service and http handlers",
+	Long: `Adds a DDD vertical slice for an entity to a project generated by stock:
+	the entity and its repository interface in domain, a service with CRUD use cases in application,
+	a repository in infrastructure/repositories and http handlers for the project framework
+	in infrastructure/transport/http. Imports use the module path from the project go.mod.
+	The repository stores the entity in the project database, a table (or collection) named after the plural
+	of the entity that a migration has to create, and in memory when the project has no database.
+	The handlers are built in buildDependencies() and their routes registered in httpRouter() of the project main.go,
+	running the command again for the same entity does not register it twice.
+
+	Field types: ` + strings.Join(entity.Types(), ", ") + `
+	An id:uuid field is added when --fields has no id.
+
+	Example:
+	stock add:entity Order --fields id:uuid,total:decimal,status:string
+	stock add:entity order_item --fields id:int,quantity:int -d ./project --dry-run
+	`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		spec, err := cmd.Flags().GetString("fields")
+		if err != nil {
+			return err
+		}
+		project, err := wizard.LoadProject(dir)
+		if err != nil {
+			return err
+		}
+		fields, err := entity.ParseFields(spec)
+		if err != nil {
+			return err
+		}
+		e, err := entity.New(args[0], fields, project)
+		if err != nil {
+			return err
+		}
+		noRegister, err := cmd.Flags().GetBool("no-register")
+		if err != nil {
+			return err
+		}
+		objects := e.Objects(wizard.FrameworkByName(project.Framework))
+		register := !noRegister && project.MainFile != ""
+		if register {
+			objects = append(objects, e.RouterObject(project.MainFile))
+		}
+		if err = generateObjects(cmd, entity.NewConfig(project, e, ""), objects); err != nil {
+			return err
+		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); !dryRun && !register {
+			fmt.Fprintf(cmd.OutOrStdout(), "Wire the %s handlers into main.go:\n\n%s\n", e.Name, e.Registration())
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addEntityCmd)
+	addEntityCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	addEntityCmd.Flags().String("fields", "", "Entity fields as name:type pairs (eg. id:uuid,total:decimal,status:string)")
+	addEntityCmd.Flags().Bool("no-register", false, "Do not edit main.go, print the route registration instead")
+	addWriteFlags(addEntityCmd, wizard.ConflictFail)
+}
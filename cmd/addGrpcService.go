@@ -0,0 +1,81 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// addGrpcServiceCmd represents the add:grpc-service command
+var addGrpcServiceCmd = &cobra.Command{
+	Use:   "add:grpc-service <Name>",
+	Short: "Generate a gRPC service definition and its server",
+	Long: `Adds api/proto/{package}/v1/{name}.proto with a service definition and a server implementing it
+	in infrastructure/transport/grpc, then registers the server in grpcServer() of the project main.go.
+	Projects scaffolded with the grpc transport already have grpcServer(), the health and reflection services
+	and a buf.gen.yaml. Run buf generate api/proto before building.
+
+	Example:
+	stock add:grpc-service Order
+	stock add:grpc-service payments -d ./project --dry-run
+	`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		noRegister, err := cmd.Flags().GetBool("no-register")
+		if err != nil {
+			return err
+		}
+		project, err := wizard.LoadProject(dir)
+		if err != nil {
+			return err
+		}
+		service, err := wizard.NewGRPCService(args[0], project.ModulePath)
+		if err != nil {
+			return err
+		}
+		objects := wizard.GRPCServiceObjects(project)
+		register := !noRegister && project.MainFile != ""
+		if register {
+			objects = append(objects, service.RegisterObject(project.MainFile))
+		}
+		if err = generateObjects(cmd, wizard.NewGRPCServiceConfig(project, service, ""), objects); err != nil {
+			return err
+		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); !dryRun {
+			if !register {
+				fmt.Fprintf(cmd.OutOrStdout(), "Register the service in grpcServer():\n\n%s.Register%sServiceServer(server, grpctransport.New%sServer())\n\n",
+					service.GoAlias(), service.Name, service.Name)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Generate the go code for %s with: buf generate api/proto\n", service.ProtoFile())
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addGrpcServiceCmd)
+	addGrpcServiceCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	addGrpcServiceCmd.Flags().Bool("no-register", false, "Do not edit main.go, print the service registration instead")
+	addWriteFlags(addGrpcServiceCmd, wizard.ConflictFail)
+}
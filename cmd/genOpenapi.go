@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/openapi"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// genOpenapiCmd represents the gen:openapi command
+var genOpenapiCmd = &cobra.Command{
+	Use:   "gen:openapi <spec>",
+	Short: "Generate types, handler stubs and routes from an OpenAPI 3 document",
+	Long: `Reads an OpenAPI 3 document (.yaml or .json) and generates
+	application/{name}_api.go                            request, response and parameter types
+	infrastructure/transport/http/{name}_api.go          the {Name}API interface and request decoding
+	infrastructure/transport/http/{name}_routes.go       route registration for the project framework
+	infrastructure/transport/http/{name}_handler.go      handler stubs, the file you edit
+	The handler is built in buildDependencies() and its routes registered in httpRouter() of the project main.go.
+
+	Run it again after changing the document. Generated files are replaced, the handler keeps every method body,
+	new operations get a stub and methods whose parameters or results changed get the new signature.
+
+	Example:
+	stock gen:openapi api/openapi/service.yaml
+	stock gen:openapi api/openapi/orders.yaml --name=orders --dry-run
+	`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		name, err := cmd.Flags().GetString("name")
+		if err != nil {
+			return err
+		}
+		noRegister, err := cmd.Flags().GetBool("no-register")
+		if err != nil {
+			return err
+		}
+		project, err := wizard.LoadProject(dir)
+		if err != nil {
+			return err
+		}
+		doc, err := openapi.Load(args[0])
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		}
+		spec := args[0]
+		if rel, err := filepath.Rel(dir, spec); err == nil && !strings.HasPrefix(rel, "..") {
+			spec = rel
+		}
+		api, err := openapi.NewAPI(doc, name, filepath.ToSlash(spec), project.ModulePath, project.Framework)
+		if err != nil {
+			return err
+		}
+		objects := api.Objects(wizard.FrameworkByName(project.Framework))
+		if !noRegister && project.MainFile != "" {
+			objects = append(objects, api.RouterObject(project.MainFile))
+		}
+		return generateObjects(cmd, openapi.NewConfig(project, api, ""), objects)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genOpenapiCmd)
+	genOpenapiCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	genOpenapiCmd.Flags().String("name", "", "Prefix of the generated handler and routes, defaults to the document file name")
+	genOpenapiCmd.Flags().Bool("no-register", false, "Do not edit main.go")
+	addWriteFlags(genOpenapiCmd, wizard.ConflictFail)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/schema"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// genRepoCmd represents the gen:repo command
+var genRepoCmd = &cobra.Command{
+	Use:   "gen:repo <schema.sql>",
+	Short: "Generate domain structs and sql repositories from CREATE TABLE statements",
+	Long: `Reads the CREATE TABLE statements of a MySQL or Postgres schema, eg. mysqldump --no-data or
+	pg_dump --schema-only output, and generates for every table a struct with db and json tags in domain,
+	its repository interface and a database/sql implementation in infrastructure/repositories.
+	Primary keys, auto increment, serial and identity columns added by ALTER TABLE are picked up as well.
+
+	Nullable columns become pointers (--null=pointer) or database/sql null types (--null=sql),
+	dates and timestamps time.Time, json columns json.RawMessage and blobs []byte.
+	Queries are written for the project database, --dialect overrides it: ` + strings.Join(schema.Dialects(), ", ") + `
+
+	Example:
+	stock gen:repo schema.sql
+	stock gen:repo dump.sql --tables=orders,order_items --null=sql -d ./project
+	`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		dialect, err := cmd.Flags().GetString("dialect")
+		if err != nil {
+			return err
+		}
+		null, err := cmd.Flags().GetString("null")
+		if err != nil {
+			return err
+		}
+		only, err := cmd.Flags().GetStringSlice("tables")
+		if err != nil {
+			return err
+		}
+		project, err := wizard.LoadProject(dir)
+		if err != nil {
+			return err
+		}
+		if dialect == "" {
+			if project.Database == "mongodb" || project.Database == "none" {
+				return fmt.Errorf("the project has no sql database, pass --dialect (%s)", strings.Join(schema.Dialects(), ", "))
+			}
+			dialect = project.Database
+		}
+		src, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		tables, err := schema.Parse(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		if tables, err = selectTables(tables, only); err != nil {
+			return err
+		}
+
+		var objects []*wizard.Object
+		if _, err = os.Stat(filepath.Join(dir, schema.BaseRepositoryFile)); os.IsNotExist(err) {
+			objects = append(objects, schema.BaseRepositoryObject())
+		}
+		names := map[string]string{}
+		var models []*schema.Model
+		for _, table := range tables {
+			model, err := schema.NewModel(table, project.ModulePath, null, dialect)
+			if err != nil {
+				return err
+			}
+			if other, ok := names[model.Name]; ok {
+				return fmt.Errorf("tables %s and %s would both generate %s, leave one out with --tables", other, table.Name, model.Name)
+			}
+			names[model.Name] = table.Name
+			models = append(models, model)
+			objects = append(objects, model.Objects()...)
+		}
+		config := wizard.NewConfig(project.Names.ProjectName, project.ModulePath, project.Names.Maintainer, project)
+		config.OutputDir = dir
+		if err = generateObjects(cmd, config, objects); err != nil {
+			return err
+		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); !dryRun {
+			fmt.Fprintln(cmd.OutOrStdout(), "Build the repositories with the *sql.DB from buildDependencies():")
+			for _, x := range models {
+				fmt.Fprintf(cmd.OutOrStdout(), "\trepositories.New%sSQLRepository(db)\n", x.Name)
+			}
+		}
+		return nil
+	},
+}
+
+//selectTables tables with the given names in schema order, every table when names is empty
+func selectTables(tables []*schema.Table, names []string) ([]*schema.Table, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no CREATE TABLE statements found")
+	}
+	if len(names) == 0 {
+		return tables, nil
+	}
+	var selected []*schema.Table
+	for _, name := range names {
+		found := false
+		for _, x := range tables {
+			if strings.EqualFold(x.Name, name) {
+				selected = append(selected, x)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("table %s is not in the schema", name)
+		}
+	}
+	return selected, nil
+}
+
+func init() {
+	rootCmd.AddCommand(genRepoCmd)
+	genRepoCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	genRepoCmd.Flags().String("dialect", "", "Write queries for this database instead of the project one: "+strings.Join(schema.Dialects(), ", "))
+	genRepoCmd.Flags().String("null", schema.NullPointer, "Nullable columns as: "+strings.Join(schema.NullStyles(), ", "))
+	genRepoCmd.Flags().StringSlice("tables", nil, "Only generate these tables")
+	addWriteFlags(genRepoCmd, wizard.ConflictFail)
+}
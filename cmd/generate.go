@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+//addGenerateFlags flags shared by every command that ends in wizard.Execute
+func addGenerateFlags(cmd *cobra.Command, conflictPolicy string) {
+	cmd.Flags().StringP("output", "o", "", "Generate the project inside this directory instead of the current one")
+	cmd.Flags().Bool("no-tidy", false, "Do not run go mod tidy in the generated project, make build writes go.sum instead")
+	addWriteFlags(cmd, conflictPolicy)
+}
+
+//addWriteFlags conflict and dry run flags shared by every command that writes files
+func addWriteFlags(cmd *cobra.Command, conflictPolicy string) {
+	cmd.Flags().String("on-conflict", conflictPolicy, "What to do with existing files that differ: "+strings.Join(wizard.ConflictPolicies(), ", "))
+	addKeepInvalidFlag(cmd)
+	addDryRunFlags(cmd)
+}
+
+//addKeepInvalidFlag lets a command write generated files that do not parse instead of failing
+func addKeepInvalidFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("keep-invalid", false, "Write generated files that do not parse and report the problems instead of failing")
+}
+
+//addDryRunFlags flags of commands that can print what they would write instead of writing it
+func addDryRunFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Print the planned project tree without touching the disk")
+	cmd.Flags().String("format", wizard.PlanFormatTree, "Dry run output format: tree or json")
+	cmd.Flags().Bool("preview", false, "Include rendered file contents in the dry run output")
+}
+
+//generate runs wizard.Execute or, with --dry-run, prints the plan instead
+func generate(cmd *cobra.Command, opts *wizard.Options) error {
+	var err error
+	if opts.OutputDir, err = cmd.Flags().GetString("output"); err != nil {
+		return err
+	}
+	if opts.ConflictPolicy, err = cmd.Flags().GetString("on-conflict"); err != nil {
+		return err
+	}
+	if opts.KeepInvalid, err = cmd.Flags().GetBool("keep-invalid"); err != nil {
+		return err
+	}
+	//the project directory, regenerate and the add commands get it as --dir and use the same project templates
+	opts.Complete()
+	templates.SetProjectRoot(filepath.Join(opts.OutputDir, opts.ProjectName))
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		if err = wizard.Execute(opts); err != nil {
+			return err
+		}
+		noTidy, err := cmd.Flags().GetBool("no-tidy")
+		if err != nil {
+			return err
+		}
+		if !noTidy {
+			tidy(cmd, filepath.Join(opts.OutputDir, opts.ProjectName))
+		}
+		return nil
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	preview, err := cmd.Flags().GetBool("preview")
+	if err != nil {
+		return err
+	}
+	entries, err := wizard.Plan(opts, preview)
+	if err != nil {
+		return err
+	}
+	return wizard.PrintPlan(cmd.OutOrStdout(), entries, format)
+}
+
+//tidy writes go.sum of the generated project. The project is kept when it fails, eg. without network,
+//make build runs go mod tidy first when go.sum is missing
+func tidy(cmd *cobra.Command, dir string) {
+	//a custom blueprint does not have to put go.mod in the project root
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Running go mod tidy in", dir)
+	if err := wizard.Tidy(dir); err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "go.sum was not written, run make tidy in %s: %v\n", dir, err)
+	}
+}
+
+//generateObjects writes objects with config or, with --dry-run, prints the plan instead.
+//Commands without --on-conflict keep the policy of config
+func generateObjects(cmd *cobra.Command, config *wizard.Config, objects []*wizard.Object) error {
+	var err error
+	if cmd.Flags().Lookup("on-conflict") != nil {
+		if config.ConflictPolicy, err = cmd.Flags().GetString("on-conflict"); err != nil {
+			return err
+		}
+	}
+	//the flag can only allow more, regenerate keeps files with conflict markers without it
+	keepInvalid, err := cmd.Flags().GetBool("keep-invalid")
+	if err != nil {
+		return err
+	}
+	config.KeepInvalid = config.KeepInvalid || keepInvalid
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		return wizard.Generate(config, objects)
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	preview, err := cmd.Flags().GetBool("preview")
+	if err != nil {
+		return err
+	}
+	entries, err := wizard.PlanObjects(config, objects, preview)
+	if err != nil {
+		return err
+	}
+	return wizard.PrintPlan(cmd.OutOrStdout(), entries, format)
+}
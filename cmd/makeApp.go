@@ -0,0 +1,132 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// makeAppCmd represents the make:app command
+var makeAppCmd = &cobra.Command{
+	Use:   "make:app",
+	Short: "Generate a project scaffold without prompts",
+	Long: `Non-interactive version of the wiz command. Every wizard answer can be given as a flag
+	or read from an answers file (.yaml, .yml or .json). Flags override values from the answers file.
+
+	Example:
+	stock make:app -n=github.com/AkronimBlack/project --framework=gin
+	stock make:app -n=github.com/AkronimBlack/mailer --archetype=worker --database=postgres
+	stock make:app -n=github.com/AkronimBlack/project --modules=metrics,cache
+	stock make:app --answers=answers.yaml
+	stock make:app --answers=answers.yaml --dry-run --format=json
+
+	Answers file example:
+	full_name: github.com/AkronimBlack/project
+	archetype: rest
+	framework: gin
+	transports: [http, grpc]
+	database: postgres
+	migrations: true
+	modules: [metrics, auth]
+	license: MIT
+	contributing: true
+	`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := optionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		return generate(cmd, opts)
+	},
+}
+
+func optionsFromFlags(cmd *cobra.Command) (*wizard.Options, error) {
+	opts := &wizard.Options{}
+	answersFile, err := cmd.Flags().GetString("answers")
+	if err != nil {
+		return nil, err
+	}
+	if answersFile != "" {
+		if opts, err = wizard.LoadOptions(answersFile); err != nil {
+			return nil, err
+		}
+	}
+
+	flags := map[string]*string{
+		"name":         &opts.FullName,
+		"project-name": &opts.ProjectName,
+		"maintainer":   &opts.Maintainer,
+		"archetype":    &opts.Archetype,
+		"framework":    &opts.Framework,
+		"database":     &opts.Database,
+		"blueprint":    &opts.Blueprint,
+		"go-version":   &opts.GoVersion,
+		"license":      &opts.License,
+	}
+	for flag, field := range flags {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		if *field, err = cmd.Flags().GetString(flag); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Changed("transports") {
+		if opts.Transports, err = cmd.Flags().GetStringSlice("transports"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Changed("modules") {
+		if opts.Modules, err = cmd.Flags().GetStringSlice("modules"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Changed("migrations") {
+		if opts.Migrations, err = cmd.Flags().GetBool("migrations"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Changed("contributing") {
+		if opts.Contributing, err = cmd.Flags().GetBool("contributing"); err != nil {
+			return nil, err
+		}
+	}
+	opts.Complete()
+	return opts, opts.Validate()
+}
+
+func init() {
+	rootCmd.AddCommand(makeAppCmd)
+	makeAppCmd.Flags().StringP("name", "n", "", "Full project name (eg. github.com/AkronimBlack/project)")
+	makeAppCmd.Flags().String("project-name", "", "Project name, defaults to the last part of the full name")
+	makeAppCmd.Flags().String("maintainer", "", "Maintainer, defaults to the second to last part of the full name")
+	makeAppCmd.Flags().String("archetype", wizard.DefaultArchetype(), "Kind of project: "+strings.Join(wizard.Archetypes(), ", "))
+	makeAppCmd.Flags().StringP("framework", "f", wizard.DefaultHTTPFramework(), "Http framework to use, rest projects only")
+	makeAppCmd.Flags().StringSliceP("transports", "t", wizard.DefaultTransports(), "Transports the service is reachable through: "+strings.Join(wizard.Transports(), ", "))
+	makeAppCmd.Flags().String("database", wizard.DefaultDatabase(), "Database the project connects to: "+strings.Join(wizard.Databases(), ", "))
+	makeAppCmd.Flags().Bool("migrations", false, "Generate a migrations directory and runner, needs a sql database: "+strings.Join(wizard.SQLDatabases(), ", "))
+	makeAppCmd.Flags().StringSlice("modules", nil, "Feature modules of services: "+strings.Join(wizard.Modules(), ", "))
+	makeAppCmd.Flags().String("license", wizard.DefaultLicense(), "License of the generated LICENSE: "+strings.Join(wizard.Licenses(), ", "))
+	makeAppCmd.Flags().Bool("contributing", false, "Generate CONTRIBUTING.md")
+	makeAppCmd.Flags().String("go-version", wizard.DefaultGoVersion(), "Go version of the generated go.mod")
+	makeAppCmd.Flags().StringP("blueprint", "b", "", "Build the project tree from a .yaml or .json blueprint instead of the built-in one")
+	makeAppCmd.Flags().StringP("answers", "a", "", "Read answers from a .yaml or .json file")
+	addGenerateFlags(makeAppCmd, wizard.ConflictFail)
+}
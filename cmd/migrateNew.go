@@ -0,0 +1,77 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// migrateNewCmd represents the migrate:new command
+var migrateNewCmd = &cobra.Command{
+	Use:   "migrate:new <name>",
+	Short: "Create the up and down SQL files of a new migration",
+	Long: `Creates {version}_{name}.up.sql and {version}_{name}.down.sql in the migrations directory of the project,
+	the version is the current UTC time so migrations apply in the order they were created.
+	Projects generated with --migrations embed the files in the service binary and apply them with
+	"{binary} migrate up", or on start when DB_MIGRATE is true.
+
+	Example:
+	stock migrate:new create_orders
+	stock migrate:new "add order status" -d ./project
+	`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		migrationsDir, err := cmd.Flags().GetString("migrations")
+		if err != nil {
+			return err
+		}
+		project, err := wizard.LoadProject(dir)
+		if err != nil {
+			return err
+		}
+		objects, err := wizard.MigrationObjects(filepath.ToSlash(migrationsDir), args[0], time.Now())
+		if err != nil {
+			return err
+		}
+		config := wizard.NewConfig(project.Names.ProjectName, project.ModulePath, project.Names.Maintainer, project)
+		config.OutputDir = dir
+		if err = generateObjects(cmd, config, objects); err != nil {
+			return err
+		}
+		if _, err = os.Stat(filepath.Join(dir, migrationsDir, "migrations.go")); os.IsNotExist(err) {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s has no migration runner, generate the project with --migrations or apply the files with your own tool\n", migrationsDir)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateNewCmd)
+	migrateNewCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	migrateNewCmd.Flags().String("migrations", wizard.MigrationsDir, "Migrations directory, relative to the project directory")
+	addWriteFlags(migrateNewCmd, wizard.ConflictFail)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//execute runs stock with args and returns what it wrote
+func execute(args ...string) (string, error) {
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+//goCommand runs the go tool in dir and fails the test with its output when it fails
+func goCommand(t *testing.T, dir string, args ...string) {
+	command := exec.Command("go", args...)
+	command.Dir = dir
+	if out, err := command.CombinedOutput(); err != nil {
+		t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+//migrate runs the migrate subcommand of the service binary against the sqlite database at dbPath
+func migrate(dir, binary, dbPath string, args ...string) (string, error) {
+	command := exec.Command(binary, append([]string{"migrate"}, args...)...)
+	command.Dir = dir
+	command.Env = append(os.Environ(), "DB_PATH="+dbPath)
+	out, err := command.CombinedOutput()
+	return string(out), err
+}
+
+func writeFile(t *testing.T, name, content string) {
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//TestMigrateSQLite generates a sqlite service with migrations, adds a migration with migrate:new and runs
+//migrate up, down and status of the built service. It tidies and builds the project, so it needs the go tool and the modules
+func TestMigrateSQLite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a generated project")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go tool not found")
+	}
+	//without the module proxy go mod tidy -e succeeds but writes no go.sum
+	probe := exec.Command("go", "mod", "download", "modernc.org/sqlite@latest")
+	probe.Dir = t.TempDir()
+	if out, err := probe.CombinedOutput(); err != nil {
+		t.Skipf("module proxy unreachable: %v\n%s", err, out)
+	}
+	out, err := execute("make:app", "-n", "example.com/acme/shop", "--database", "sqlite", "--migrations",
+		"-o", t.TempDir(), "--on-conflict", "fail", "--no-tidy=false")
+	if err != nil {
+		t.Fatalf("make:app: %v\n%s", err, out)
+	}
+	project := filepath.Join(makeAppCmd.Flag("output").Value.String(), "shop")
+	//go build refuses modules without go.sum, make:app writes it with go mod tidy
+	if _, err = os.Stat(filepath.Join(project, "go.sum")); err != nil {
+		t.Fatalf("make:app did not write go.sum: %v\n%s", err, out)
+	}
+	migrations := filepath.Join(project, "migrations")
+	if out, err = execute("migrate:new", "create_orders", "-d", project, "--on-conflict", "fail"); err != nil {
+		t.Fatalf("migrate:new: %v\n%s", err, out)
+	}
+	ups, err := filepath.Glob(filepath.Join(migrations, "*_create_orders.up.sql"))
+	if err != nil || len(ups) != 1 {
+		t.Fatalf("migrate:new wrote %v, want one create_orders migration", ups)
+	}
+	up := ups[0]
+	version := strings.TrimSuffix(filepath.Base(up), "_create_orders.up.sql")
+	createOrders := version + "_create_orders"
+	writeFile(t, up, "CREATE TABLE orders (id INTEGER PRIMARY KEY, total INTEGER NOT NULL);\n")
+	writeFile(t, strings.TrimSuffix(up, ".up.sql")+".down.sql", "DROP TABLE orders;\n")
+
+	binary := filepath.Join(t.TempDir(), "shop")
+	build := func() {
+		goCommand(t, project, "build", "-o", binary, "./cmd/shop")
+	}
+	build()
+	dbPath := filepath.Join(t.TempDir(), "shop.db")
+
+	steps := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"status"}, []string{"pending  000001_init", "pending  " + createOrders}},
+		{[]string{"up"}, []string{"applying 000001_init", "applying " + createOrders}},
+		{[]string{"status"}, []string{"applied  000001_init", "applied  " + createOrders}},
+		{[]string{"down"}, []string{"rolling back " + createOrders}},
+		{[]string{"status"}, []string{"applied  000001_init", "pending  " + createOrders}},
+		//CREATE TABLE fails unless the down migration dropped the table
+		{[]string{"up"}, []string{"applying " + createOrders}},
+		{[]string{"down", "2"}, []string{"rolling back " + createOrders, "rolling back 000001_init"}},
+		{[]string{"status"}, []string{"pending  000001_init", "pending  " + createOrders}},
+	}
+	for _, x := range steps {
+		out, err := migrate(project, binary, dbPath, x.args...)
+		if err != nil {
+			t.Fatalf("migrate %s: %v\n%s", strings.Join(x.args, " "), err, out)
+		}
+		if got := strings.Split(strings.TrimSpace(out), "\n"); strings.Join(got, "\n") != strings.Join(x.want, "\n") {
+			t.Errorf("migrate %s printed\n%s\nwant\n%s", strings.Join(x.args, " "), out, strings.Join(x.want, "\n"))
+		}
+	}
+	for _, args := range [][]string{{"down", "0"}, {"sideways"}} {
+		if out, err := migrate(project, binary, dbPath, args...); err == nil {
+			t.Errorf("migrate %s passed\n%s", strings.Join(args, " "), out)
+		}
+	}
+
+	//files the runner refuses, every one is embedded into the binary so it has to be built again
+	broken := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"duplicate version", version + "_create_customers.up.sql", "share a version"},
+		{"no version", "create_customers.up.sql", "is not named {version}_{name}"},
+		{"no direction", version + "_create_customers.sql", "is neither .up.sql nor .down.sql"},
+	}
+	for _, x := range broken {
+		t.Run(x.name, func(t *testing.T) {
+			file := filepath.Join(migrations, x.file)
+			writeFile(t, file, "SELECT 1;\n")
+			defer os.Remove(file)
+			build()
+			out, err := migrate(project, binary, dbPath, "status")
+			if err == nil || !strings.Contains(out, x.wantErr) {
+				t.Errorf("migrate status: %v\n%s\nwant an error containing %q", err, out, x.wantErr)
+			}
+		})
+	}
+}
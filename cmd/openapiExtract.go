@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/AkronimBlack/stock/pkg/diff"
+	"github.com/AkronimBlack/stock/pkg/openapi"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+const extractHeader = "# Generated by stock openapi:extract from the routes registered in httpRouter(). Only info is kept when it runs again.\n"
+
+// openapiExtractCmd represents the openapi:extract command
+var openapiExtractCmd = &cobra.Command{
+	Use:   "openapi:extract",
+	Short: "Derive an OpenAPI 3 document from the routes of a project",
+	Long: `Reads the routes registered in httpRouter() of the project main.go, following every function the router
+	is passed to, and writes an OpenAPI 3 document to api/openapi. Request bodies, responses and their schemas
+	come from the types the handlers bind and write, query and header parameters from the values they read.
+	Nothing is compiled, the project only has to parse. Supported frameworks are gin, gorilla, chi, echo and net/http.
+
+	With --check nothing is written, the command fails when the document on disk is not up to date.
+	Run it in CI to catch routes that changed without the document.
+	A document that differs is replaced, --on-conflict=backup keeps a copy and --on-conflict=fail refuses to write it.
+
+	Example:
+	stock openapi:extract
+	stock openapi:extract --output=api/openapi/orders.yaml
+	stock openapi:extract --check
+	`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		check, err := cmd.Flags().GetBool("check")
+		if err != nil {
+			return err
+		}
+		project, err := wizard.LoadProject(dir)
+		if err != nil {
+			return err
+		}
+		//a missing document is written, or reported as out of date with --check
+		existing, _ := ioutil.ReadFile(filepath.Join(dir, output))
+
+		//title, version and description are written by people, keep them
+		info := openapi.Info{Title: project.Names.ProjectName, Version: "0.1.0"}
+		if current, err := openapi.Parse(existing); err == nil && current.Info.Title != "" {
+			info = current.Info
+		}
+		doc, err := openapi.Extract(project, info)
+		if err != nil {
+			return err
+		}
+		content, err := doc.Marshal()
+		if err != nil {
+			return err
+		}
+		content = append([]byte(extractHeader), content...)
+
+		if check {
+			if bytes.Equal(existing, content) {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", output)
+				return nil
+			}
+			fmt.Fprint(cmd.OutOrStdout(), diff.Unified(output, output+" (extracted)", existing, content))
+			return fmt.Errorf("%s is out of date, run stock openapi:extract", output)
+		}
+
+		config := wizard.NewConfig(project.Names.ProjectName, project.ModulePath, project.Names.Maintainer, project)
+		config.OutputDir = dir
+		//written as content, not as an edit, so --on-conflict decides what happens with a document that differs
+		object := &wizard.Object{
+			Name:    filepath.ToSlash(output),
+			Type:    wizard.TypeFile,
+			Content: content,
+		}
+		return generateObjects(cmd, config, []*wizard.Object{object})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openapiExtractCmd)
+	openapiExtractCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	openapiExtractCmd.Flags().String("output", "api/openapi/openapi.yaml", "Document to write, relative to the project directory")
+	openapiExtractCmd.Flags().Bool("check", false, "Fail when the document on disk differs from the routes instead of writing it")
+	addWriteFlags(openapiExtractCmd, wizard.ConflictOverwrite)
+}
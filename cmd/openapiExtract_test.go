@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//copyDir copies the fixture project in src to a temporary directory
+func copyDir(t *testing.T, src string) string {
+	dst := t.TempDir()
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dst, rel), b, 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+//extract runs openapi:extract on dir, every flag is given so no value is left over from an earlier run
+func extract(dir string, check bool, onConflict string) (string, error) {
+	args := []string{"openapi:extract", "-d", dir, "--output", "api/openapi/openapi.yaml", "--on-conflict", onConflict}
+	if check {
+		args = append(args, "--check")
+	} else {
+		args = append(args, "--check=false")
+	}
+	return execute(args...)
+}
+
+func TestOpenapiExtractCheck(t *testing.T) {
+	dir := copyDir(t, filepath.Join("..", "pkg", "openapi", "testdata", "extract", "chi"))
+	document := filepath.Join(dir, "api", "openapi", "openapi.yaml")
+
+	if _, err := extract(dir, true, "overwrite"); err == nil {
+		t.Fatal("--check passed without a document")
+	}
+	if _, err := os.Stat(document); !os.IsNotExist(err) {
+		t.Fatal("--check wrote the document")
+	}
+	if _, err := extract(dir, false, "overwrite"); err != nil {
+		t.Fatal(err)
+	}
+	written, err := ioutil.ReadFile(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := extract(dir, true, "overwrite"); err != nil || !strings.Contains(out, "is up to date") {
+		t.Fatalf("--check after writing the document: %v\n%s", err, out)
+	}
+
+	//a new route makes the document out of date
+	handler := filepath.Join(dir, "infrastructure", "transport", "http", "order_handler.go")
+	src, err := ioutil.ReadFile(handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src = bytes.Replace(src, []byte(`router.Post("/orders", handler.Create)`),
+		[]byte(`router.Post("/orders", handler.Create)
+	router.Put("/orders/{id}", handler.Create)`), 1)
+	if err = ioutil.WriteFile(handler, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := extract(dir, true, "overwrite")
+	if err == nil || !strings.Contains(out, "+    put:") {
+		t.Fatalf("--check passed with a new route: %v\n%s", err, out)
+	}
+
+	tests := []struct {
+		onConflict string
+		fails      bool
+		changed    bool
+	}{
+		{onConflict: "fail", fails: true},
+		{onConflict: "skip"},
+		{onConflict: "overwrite", changed: true},
+	}
+	for _, tt := range tests {
+		_, err := extract(dir, false, tt.onConflict)
+		if (err != nil) != tt.fails {
+			t.Errorf("--on-conflict=%s: error %v", tt.onConflict, err)
+		}
+		current, err := ioutil.ReadFile(document)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed := !bytes.Equal(current, written); changed != tt.changed {
+			t.Errorf("--on-conflict=%s: document changed %v, want %v", tt.onConflict, changed, tt.changed)
+		}
+	}
+	if _, err := extract(dir, true, "overwrite"); err != nil {
+		t.Errorf("--check after overwriting: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"github.com/spf13/cobra"
+)
+
+// regenerateCmd represents the regenerate command
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate",
+	Short: "Render a generated project again with the current templates and merge them with your changes",
+	Long: `Reads the answers make:app or wiz recorded in .stock.yaml, renders the project again and three-way merges
+	every file. Files you did not change are replaced, files you changed get the template changes merged in,
+	lines both you and the templates changed are kept between conflict markers the way git marks them:
+
+	<<<<<<< current
+	your lines
+	=======
+	regenerated lines
+	>>>>>>> regenerated
+
+	Files you deleted are not created again and files the templates no longer generate are left as they are.
+	The base of the merge are the copies of the generated files kept in .stock, commit them with the project.
+
+	Example:
+	stock regenerate
+	stock regenerate -d ./project --dry-run
+	`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		config, objects, files, err := wizard.Regenerate(dir)
+		if err != nil {
+			return err
+		}
+		conflicts := 0
+		for _, x := range files {
+			switch x.Status {
+			case wizard.RegenerateUnchanged:
+				continue
+			case wizard.RegenerateConflict:
+				conflicts++
+				fmt.Fprintf(cmd.OutOrStdout(), "%-9s %s (%d)\n", x.Status, x.Path, x.Conflicts)
+			default:
+				fmt.Fprintf(cmd.OutOrStdout(), "%-9s %s\n", x.Status, x.Path)
+			}
+		}
+		if err = generateObjects(cmd, config, objects); err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d files have conflicts, resolve the conflict markers and remove them\n", conflicts)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(regenerateCmd)
+	regenerateCmd.Flags().StringP("dir", "d", ".", "Project directory")
+	addKeepInvalidFlag(regenerateCmd)
+	addDryRunFlags(regenerateCmd)
+}
@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/AkronimBlack/stock/pkg/wizard"
 	"github.com/spf13/cobra"
 
 	homedir "github.com/mitchellh/go-homedir"
@@ -33,6 +35,25 @@ var rootCmd = &cobra.Command{
 	Short: "Development tools to help with microservice development and tools usage",
 	Long: `Tools that are supported:
 	`,
+	//Execute prints the error, cobra printing it as well shows it twice
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setProjectTemplates(cmd)
+	},
+}
+
+//setProjectTemplates looks project templates up in --dir of commands working on a project.
+//Commands generating a project look them up in the project directory once its name is known, see generate
+func setProjectTemplates(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup("dir") == nil {
+		return nil
+	}
+	dir, err := cmd.Flags().GetString("dir")
+	if err != nil {
+		return err
+	}
+	templates.SetProjectRoot(dir)
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -46,6 +67,7 @@ func Execute() {
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.stock.yaml)")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -72,4 +94,16 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
+
+	//dependencies overrides versions of the go.mod catalog or adds modules, eg.
+	//dependencies: [{module: github.com/gin-gonic/gin, version: v1.9.1}]
+	var dependencies []*wizard.Dependency
+	if err := viper.UnmarshalKey("dependencies", &dependencies); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := wizard.OverrideDependencies(dependencies); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//TestErrorPrintedOnce cobra leaves printing the error of a command to Execute
+func TestErrorPrintedOnce(t *testing.T) {
+	answers := filepath.Join(t.TempDir(), "answers.json")
+	writeFile(t, answers, `{"full_name": "example.com/acme/shop", "framwork": "chi"}`)
+	defer makeAppCmd.Flags().Set("answers", "")
+	out, err := execute("make:app", "--answers", answers)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "framwork"`) {
+		t.Fatalf("error = %v, want the unknown field of the answers file", err)
+	}
+	if strings.Contains(out, "framwork") {
+		t.Errorf("the error is printed by cobra as well\n%s", out)
+	}
+}
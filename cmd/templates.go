@@ -0,0 +1,104 @@
+/*
+Copyright © 2021 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/spf13/cobra"
+)
+
+// templatesCmd represents the templates command
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Inspect and customize the templates used by the scaffold generator",
+	Long: `Every template can be overridden by a file with the same name under .stock-templates in the
+	project directory (project) or under ~/.stock/templates (user). Project templates win over user templates,
+	user templates win over the built-in ones.
+
+	Example:
+	stock templates list
+	stock templates show gin/main.go
+	stock templates eject Dockerfile --user
+	`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates and the source each one is loaded from",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := templates.List()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSOURCE\tPATH")
+		for _, x := range list {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", x.Name, x.Source, x.Path)
+		}
+		return w.Flush()
+	},
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a template as it would be used",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := templates.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "# %s (%s: %s)\n", t.Name, t.Source, t.Path)
+		_, err = cmd.OutOrStdout().Write(t.Content)
+		return err
+	},
+}
+
+var templatesEjectCmd = &cobra.Command{
+	Use:   "eject <name>...",
+	Short: "Copy built-in templates into the project (or user) templates directory to customize them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		user, err := cmd.Flags().GetBool("user")
+		if err != nil {
+			return err
+		}
+		dir := templates.ProjectDir()
+		if user {
+			if dir, err = templates.UserDir(); err != nil {
+				return err
+			}
+		}
+		for _, name := range args {
+			p, err := templates.Eject(name, dir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Ejected", name, "to", p)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesListCmd, templatesShowCmd, templatesEjectCmd)
+	templatesCmd.PersistentFlags().StringP("dir", "d", ".", "Project directory")
+	templatesEjectCmd.Flags().Bool("user", false, "Eject into ~/.stock/templates instead of the project templates directory")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//TestProjectTemplates make:app and regenerate use the templates in .stock-templates of the project directory
+func TestProjectTemplates(t *testing.T) {
+	output := t.TempDir()
+	project := filepath.Join(output, "shop")
+	for _, dir := range []string{output, project} {
+		if err := os.MkdirAll(filepath.Join(dir, ".stock-templates"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//the output directory is not the project, its templates are not used
+	writeFile(t, filepath.Join(output, ".stock-templates", "Makefile.tmpl"), "output: {{.Names.BinaryName}}\n")
+	writeFile(t, filepath.Join(project, ".stock-templates", "Makefile.tmpl"), "project: {{.Names.BinaryName}}\n")
+
+	out, err := execute("make:app", "-n", "example.com/acme/shop", "-o", output, "--on-conflict", "fail", "--no-tidy", "--dry-run=false")
+	if err != nil {
+		t.Fatalf("make:app: %v\n%s", err, out)
+	}
+	makefile := filepath.Join(project, "Makefile")
+	b, err := ioutil.ReadFile(makefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "project: shop\n" {
+		t.Fatalf("Makefile = %q, want the project template", b)
+	}
+
+	if out, err = execute("regenerate", "-d", project, "--dry-run=false"); err != nil {
+		t.Fatalf("regenerate: %v\n%s", err, out)
+	}
+	if strings.Contains(out, "Makefile") {
+		t.Errorf("regenerate changed the Makefile\n%s", out)
+	}
+	if b, err = ioutil.ReadFile(makefile); err != nil || string(b) != "project: shop\n" {
+		t.Errorf("Makefile = %q, %v after regenerate, want the project template", b, err)
+	}
+}
+
+//TestInvalidTemplate a project template rendering go code that does not parse fails make:app without writing the project,
+//--keep-invalid writes it anyway
+func TestInvalidTemplate(t *testing.T) {
+	output := t.TempDir()
+	project := filepath.Join(output, "shop")
+	if err := os.MkdirAll(filepath.Join(project, ".stock-templates", "gin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(project, ".stock-templates", "gin", "main.go.tmpl"), "package main\n\nfunc main() {\n")
+	defer makeAppCmd.Flags().Set("keep-invalid", "false")
+
+	args := []string{"make:app", "-n", "example.com/acme/shop", "-f", "gin", "-o", output, "--on-conflict", "fail", "--no-tidy", "--dry-run=false"}
+	_, err := execute(args...)
+	want := filepath.Join(project, "cmd", "shop", "main.go") + ":3: expected '}', found 'EOF'"
+	if err == nil || !strings.Contains(err.Error(), "nothing was written") || !strings.Contains(err.Error(), want) {
+		t.Fatalf("error = %v, want the problem %s", err, want)
+	}
+	if _, err = os.Stat(filepath.Join(project, "go.mod")); !os.IsNotExist(err) {
+		t.Fatalf("the project was written: %v", err)
+	}
+
+	if out, err := execute(append(args, "--keep-invalid")...); err != nil {
+		t.Fatalf("make:app --keep-invalid: %v\n%s", err, out)
+	}
+	if _, err = os.Stat(filepath.Join(project, "cmd", "shop", "main.go")); err != nil {
+		t.Fatal(err)
+	}
+}
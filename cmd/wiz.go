@@ -31,8 +31,8 @@ var wizCmd = &cobra.Command{
 	Long:  `Add-on to the scaffold generator that will let you customize your generated project.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		answers := struct {
-			ProjectName   string `json:"project_name"`
-			HTTPFramework string `survey:"http_framework" json:"http_framework"`
+			ProjectName string `json:"project_name"`
+			Archetype   string `survey:"archetype" json:"archetype"`
 		}{}
 
 		// perform the questions
@@ -42,8 +42,89 @@ var wizCmd = &cobra.Command{
 			return
 		}
 		common.LogJson(answers)
-		nameData := common.ExtractNameData(common.SanitizeName(answers.ProjectName))
-		wizard.Execute(wizard.NewOptions(nameData.ProjectName, nameData.Maintainer, answers.HTTPFramework, answers.ProjectName))
+		opts := &wizard.Options{FullName: answers.ProjectName, Archetype: answers.Archetype}
+		opts.Complete()
+		kind := opts.Kind()
+		if kind.HTTP {
+			err = survey.AskOne(&survey.Select{
+				Message: "Choose a http framework:",
+				Options: wizard.HTTPFrameworks(),
+				Default: wizard.DefaultHTTPFramework(),
+			}, &opts.Framework)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		if kind.Transports == nil {
+			err = survey.AskOne(&survey.MultiSelect{
+				Message: "Choose transports:",
+				Options: wizard.Transports(),
+				Default: wizard.DefaultTransports(),
+			}, &opts.Transports, survey.WithValidator(survey.Required))
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		if kind.Service {
+			err = survey.AskOne(&survey.Select{
+				Message: "Choose a database:",
+				Options: wizard.Databases(),
+				Default: wizard.DefaultDatabase(),
+			}, &opts.Database)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		if db := opts.DB(); db != nil && db.SQL() {
+			err = survey.AskOne(&survey.Confirm{Message: "Generate database migrations?", Default: true}, &opts.Migrations)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		if kind.Service {
+			err = survey.AskOne(&survey.MultiSelect{
+				Message: "Add feature modules:",
+				Options: wizard.Modules(),
+			}, &opts.Modules)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+		}
+		err = survey.AskOne(&survey.Select{
+			Message: "Choose a license:",
+			Options: wizard.Licenses(),
+			Default: wizard.DefaultLicense(),
+		}, &opts.License)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		err = survey.AskOne(&survey.Confirm{Message: "Generate CONTRIBUTING.md?", Default: opts.OpenSource()}, &opts.Contributing)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		opts.Blueprint, err = cmd.Flags().GetString("blueprint")
+		common.PanicOnError(err)
+
+		saveFile, err := cmd.Flags().GetString("save")
+		common.PanicOnError(err)
+		if saveFile != "" {
+			if err := opts.Save(saveFile); err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+			fmt.Println("Answers saved to", saveFile)
+		}
+
+		if err := generate(cmd, opts); err != nil {
+			fmt.Println(err.Error())
+		}
 	},
 }
 
@@ -55,15 +136,18 @@ var qs = []*survey.Question{
 		Validate: survey.Required,
 	},
 	{
-		Name: "http_framework",
+		Name: "archetype",
 		Prompt: &survey.Select{
-			Message: "Choose a http framework:",
-			Options: wizard.HTTPFrameworks(),
-			Default: wizard.DefaultHTTPFramework(),
+			Message: "What kind of project?",
+			Options: wizard.Archetypes(),
+			Default: wizard.DefaultArchetype(),
 		},
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(wizCmd)
+	addGenerateFlags(wizCmd, wizard.ConflictPrompt)
+	wizCmd.Flags().StringP("blueprint", "b", "", "Build the project tree from a .yaml or .json blueprint instead of the built-in one")
+	wizCmd.Flags().StringP("save", "s", "", "Save answers to a .yaml or .json file, replay them with make:app --answers")
 }
@@ -1,53 +1,182 @@
 package common
 
 import (
+	"fmt"
+	"go/token"
 	"log"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
+//NameData every name the generator derives from a module path
 type NameData struct {
-	Location    string `json:"location"`
-	Maintainer  string `json:"maintainer"`
+	//ModulePath full module path as used in go.mod (eg. example.com/org/service/v3)
+	ModulePath string `json:"module_path"`
+	//Location host part of the module path (eg. github.com), empty for paths without a host
+	Location string `json:"location"`
+	//Maintainer first path element after the host (eg. AkronimBlack), the host for vanity paths without one
+	Maintainer string `json:"maintainer"`
+	//ProjectName last path element without the major version suffix
 	ProjectName string `json:"project_name"`
+	//MajorVersion major version suffix (eg. v3 for example.com/service/v3 or gopkg.in/yaml.v3), empty for v0 and v1
+	MajorVersion string `json:"major_version"`
+	//PackageName valid go package name (eg. service for go-service)
+	PackageName string `json:"package_name"`
+	//DockerName lower case name safe for images, containers and compose services (eg. my-service)
+	DockerName string `json:"docker_name"`
+	//EnvPrefix prefix for environment variables (eg. MY_SERVICE)
+	EnvPrefix string `json:"env_prefix"`
+	//BinaryName name of the compiled binary and of the cmd directory (eg. my-service)
+	BinaryName string `json:"binary_name"`
 }
 
-//ExtractNameData will break fullname into parts by /
-//Part 0 will be platform (eg. github.com)
-//Part 1 will be maintainer (er. AkronimBlack)
-//Part 3 and everything after it fill be joined back by / and be set as project name
+var (
+	//majorSuffix path element that is only a major version, /v2 and up
+	majorSuffix = regexp.MustCompile(`^v([2-9]|[1-9][0-9]+)$`)
+	//gopkgSuffix gopkg.in style version suffix, yaml.v2
+	gopkgSuffix = regexp.MustCompile(`^(.+)\.(v[0-9]+)$`)
+	//modulePathChars characters allowed in a module path element
+	modulePathChars = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+)
+
+//ParseModulePath breaks a module path into its parts and derives the names used in generated files.
+//Supported forms include project, host/maintainer/project, host/org/group/project/v3,
+//gopkg.in/maintainer/project.v2 and vanity paths like go.example.com/project
+func ParseModulePath(fullname string) (*NameData, error) {
+	modulePath := strings.Trim(strings.TrimSpace(fullname), "/")
+	if modulePath == "" {
+		return nil, fmt.Errorf("module path is empty")
+	}
+	elems := strings.Split(modulePath, "/")
+	for _, x := range elems {
+		if !modulePathChars.MatchString(x) || strings.Trim(x, ".") == "" {
+			return nil, fmt.Errorf("invalid module path %s: element %q", modulePath, x)
+		}
+	}
+
+	data := &NameData{ModulePath: modulePath}
+	if len(elems) > 1 && majorSuffix.MatchString(elems[len(elems)-1]) {
+		data.MajorVersion = elems[len(elems)-1]
+		elems = elems[:len(elems)-1]
+	}
+	if len(elems) > 1 && strings.Contains(elems[0], ".") {
+		data.Location = elems[0]
+		elems = elems[1:]
+	}
+	last := elems[len(elems)-1]
+	if m := gopkgSuffix.FindStringSubmatch(last); m != nil && data.Location == "gopkg.in" {
+		last = m[1]
+		if m[2] != "v0" && m[2] != "v1" {
+			data.MajorVersion = m[2]
+		}
+	}
+	data.ProjectName = last
+	switch {
+	case len(elems) > 1:
+		data.Maintainer = elems[0]
+	case data.Location != "":
+		data.Maintainer = data.Location
+	}
+	data.derive()
+	return data, nil
+}
+
+//ExtractNameData same as ParseModulePath, invalid paths give empty names
 func ExtractNameData(fullname string) *NameData {
-	var maintainer string
-	var projectName string
-	var location string
+	data, err := ParseModulePath(fullname)
+	if err != nil {
+		return &NameData{ModulePath: fullname}
+	}
+	return data
+}
 
-	parts := strings.Split(fullname, "/")
-	if len(parts) > 1 {
-		projectName = parts[len(parts)-1]
-		parts = parts[:len(parts)-1]
-		maintainer = parts[len(parts)-1]
-		parts = parts[:len(parts)-1]
-		location = strings.Join(parts, "/")
+//Rename replaces the project name keeping the rest of the module data and derives the names again
+func (n *NameData) Rename(projectName string) {
+	if projectName == "" || projectName == n.ProjectName {
+		return
 	}
+	n.ProjectName = projectName
+	n.derive()
+}
 
-	return &NameData{
-		Location:    location,
-		Maintainer:  maintainer,
-		ProjectName: projectName,
+func (n *NameData) derive() {
+	words := SplitWords(n.ProjectName)
+	if len(words) == 0 {
+		words = []string{"app"}
+	}
+	n.DockerName = strings.Join(words, "-")
+	n.BinaryName = n.DockerName
+	n.EnvPrefix = strings.ToUpper(strings.Join(words, "_"))
+	if unicode.IsDigit(rune(n.EnvPrefix[0])) {
+		n.EnvPrefix = "APP_" + n.EnvPrefix
+	}
+	n.PackageName = packageName(n.ProjectName)
+}
+
+//packageName go convention for package names: lower case, no separators, go- prefix and -go suffix dropped
+func packageName(project string) string {
+	name := strings.ToLower(project)
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	name = b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "app" + name
+	}
+	if token.IsKeyword(name) {
+		name += "pkg"
+	}
+	return name
+}
+
+//SplitWords breaks a name into lower case words on separators and case changes, eg. "HTTPServer_id" => http, server, id
+func SplitWords(v string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(v)
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
 	}
+	flush()
+	return result
 }
 
 //SanitizeName will check for whitespaces. if exist will break the string into componenets and merge it back
 //as camelCase eg. "Some test string" => "someTestString"
 func SanitizeName(value string) string {
+	value = strings.TrimSpace(value)
 	if strings.Contains(value, " ") {
 		log.Println("Whitespace detected. Cleaning up app name ...")
-		components := strings.Split(value, " ")
+		components := strings.Fields(value)
 		for i, v := range components {
 			if i == 0 {
-				v = strings.ToLower(v)
+				components[i] = strings.ToLower(v)
 				continue
 			}
-			v = strings.Title(strings.ToLower(v))
+			components[i] = strings.Title(strings.ToLower(v))
 		}
 		value = strings.Join(components, "")
 		log.Printf("App name: %s", value)
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestParseModulePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want NameData
+	}{
+		{
+			path: "project",
+			want: NameData{ProjectName: "project", PackageName: "project", DockerName: "project", EnvPrefix: "PROJECT", BinaryName: "project"},
+		},
+		{
+			path: "github.com/AkronimBlack/stock",
+			want: NameData{Location: "github.com", Maintainer: "AkronimBlack", ProjectName: "stock", PackageName: "stock", DockerName: "stock", EnvPrefix: "STOCK", BinaryName: "stock"},
+		},
+		{
+			path: "github.com/acme/my-service/v3",
+			want: NameData{Location: "github.com", Maintainer: "acme", ProjectName: "my-service", MajorVersion: "v3", PackageName: "myservice", DockerName: "my-service", EnvPrefix: "MY_SERVICE", BinaryName: "my-service"},
+		},
+		{
+			path: "example.com/org/group/go-project/v2",
+			want: NameData{Location: "example.com", Maintainer: "org", ProjectName: "go-project", MajorVersion: "v2", PackageName: "project", DockerName: "go-project", EnvPrefix: "GO_PROJECT", BinaryName: "go-project"},
+		},
+		{
+			path: "gopkg.in/yaml.v3",
+			want: NameData{Location: "gopkg.in", Maintainer: "gopkg.in", ProjectName: "yaml", MajorVersion: "v3", PackageName: "yaml", DockerName: "yaml", EnvPrefix: "YAML", BinaryName: "yaml"},
+		},
+		{
+			path: "gopkg.in/acme/project.v2",
+			want: NameData{Location: "gopkg.in", Maintainer: "acme", ProjectName: "project", MajorVersion: "v2", PackageName: "project", DockerName: "project", EnvPrefix: "PROJECT", BinaryName: "project"},
+		},
+		{
+			path: "gopkg.in/acme/project.v1",
+			want: NameData{Location: "gopkg.in", Maintainer: "acme", ProjectName: "project", PackageName: "project", DockerName: "project", EnvPrefix: "PROJECT", BinaryName: "project"},
+		},
+		{
+			path: "go.example.com/project",
+			want: NameData{Location: "go.example.com", Maintainer: "go.example.com", ProjectName: "project", PackageName: "project", DockerName: "project", EnvPrefix: "PROJECT", BinaryName: "project"},
+		},
+		{
+			path: "github.com/acme/9lives",
+			want: NameData{Location: "github.com", Maintainer: "acme", ProjectName: "9lives", PackageName: "app9lives", DockerName: "9lives", EnvPrefix: "APP_9LIVES", BinaryName: "9lives"},
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.path, func(t *testing.T) {
+			got, err := ParseModulePath(x.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x.want.ModulePath = x.path
+			if *got != x.want {
+				t.Errorf("got %+v\nwant %+v", *got, x.want)
+			}
+		})
+	}
+}
+
+func TestParseModulePathTrims(t *testing.T) {
+	got, err := ParseModulePath(" /github.com/acme/project/ ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ModulePath != "github.com/acme/project" {
+		t.Errorf("module path %q", got.ModulePath)
+	}
+}
+
+func TestParseModulePathInvalid(t *testing.T) {
+	for _, x := range []string{"", " ", "github.com//project", "github.com/a b/project", "github.com/../project", "github.com/acme/pro$ject"} {
+		if _, err := ParseModulePath(x); err == nil {
+			t.Errorf("%q parsed without an error", x)
+		}
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/AkronimBlack/stock
 
-go 1.15
+go 1.16
 
 require (
 	github.com/AlecAivazis/survey/v2 v2.2.7
@@ -10,4 +10,6 @@ require (
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/viper v1.7.1
 	github.com/valyala/bytebufferpool v1.0.0
+	golang.org/x/mod v0.4.2
+	gopkg.in/yaml.v2 v2.2.8
 )
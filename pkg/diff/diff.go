@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	//OpEqual line is present in both versions
+	OpEqual = ' '
+	//OpDelete line is only present in the old version
+	OpDelete = '-'
+	//OpInsert line is only present in the new version
+	OpInsert = '+'
+
+	contextLines = 3
+)
+
+//Op a single line of an edit script
+type Op struct {
+	Kind byte
+	Line string
+	//OldIndex and NewIndex are the line positions in the old and new version, -1 when the line is not present there
+	OldIndex int
+	NewIndex int
+}
+
+//SplitLines breaks content into lines keeping the line endings so joining them back gives the original content
+func SplitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	var lines []string
+	for len(content) > 0 {
+		i := bytes.IndexByte(content, '\n')
+		if i < 0 {
+			lines = append(lines, string(content))
+			break
+		}
+		lines = append(lines, string(content[:i+1]))
+		content = content[i+1:]
+	}
+	return lines
+}
+
+//Lines computes the shortest edit script turning a into b using the longest common subsequence of lines
+func Lines(a, b []string) []Op {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]Op, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Op{Kind: OpEqual, Line: a[i], OldIndex: i, NewIndex: j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, Op{Kind: OpDelete, Line: a[i], OldIndex: i, NewIndex: -1})
+			i++
+		default:
+			ops = append(ops, Op{Kind: OpInsert, Line: b[j], OldIndex: -1, NewIndex: j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, Op{Kind: OpDelete, Line: a[i], OldIndex: i, NewIndex: -1})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, Op{Kind: OpInsert, Line: b[j], OldIndex: -1, NewIndex: j})
+	}
+	return ops
+}
+
+//Unified returns a unified diff between old and new content, empty string if they are equal
+func Unified(oldName, newName string, old, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+	ops := Lines(SplitLines(old), SplitLines(new))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		if ops[start].Kind == OpEqual {
+			start++
+			continue
+		}
+		hunkStart := start - contextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		//extend the hunk while the next change is close enough to share context
+		end := start
+		for end < len(ops) {
+			if ops[end].Kind != OpEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].Kind == OpEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				break
+			}
+			end = next
+		}
+		hunkEnd := end + contextLines
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+		writeHunk(&out, ops[hunkStart:hunkEnd])
+		start = hunkEnd
+	}
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, ops []Op) {
+	oldStart, newStart, oldCount, newCount := -1, -1, 0, 0
+	for _, op := range ops {
+		if op.OldIndex >= 0 {
+			if oldStart < 0 {
+				oldStart = op.OldIndex
+			}
+			oldCount++
+		}
+		if op.NewIndex >= 0 {
+			if newStart < 0 {
+				newStart = op.NewIndex
+			}
+			newCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, op := range ops {
+		out.WriteByte(op.Kind)
+		out.WriteString(op.Line)
+		if !strings.HasSuffix(op.Line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start = 0
+	} else {
+		start++
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
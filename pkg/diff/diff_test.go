@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+//TestUnified the hunks are the ones diff -u prints for the same files
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "both empty",
+			old:  "",
+			new:  "",
+			want: "",
+		},
+		{
+			name: "old empty",
+			old:  "",
+			new:  "a\nb\n",
+			want: "@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "new empty",
+			old:  "a\nb\n",
+			new:  "",
+			want: "@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "changes far apart",
+			old:  "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+			new:  "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nN\n",
+			want: "@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -11,4 +11,4 @@\n k\n l\n m\n-n\n+N\n",
+		},
+		{
+			name: "changes sharing context",
+			old:  "a\nb\nc\nd\ne\nf\ng\nh\n",
+			new:  "a\nB\nc\nd\ne\nf\nG\nh\n",
+			want: "@@ -1,8 +1,8 @@\n a\n-b\n+B\n c\n d\n e\n f\n-g\n+G\n h\n",
+		},
+		{
+			name: "no trailing newline",
+			old:  "a\nb",
+			new:  "a\nc",
+			want: "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			name: "trailing newline added",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			got := Unified("old.txt", "new.txt", []byte(x.old), []byte(x.new))
+			want := x.want
+			if want != "" {
+				want = "--- old.txt\n+++ new.txt\n" + want
+			}
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a\n"}},
+		{"a\n\nb", []string{"a\n", "\n", "b"}},
+	}
+	for _, x := range tests {
+		got := SplitLines([]byte(x.content))
+		if strings.Join(got, "|") != strings.Join(x.want, "|") || len(got) != len(x.want) {
+			t.Errorf("SplitLines(%q) = %q, want %q", x.content, got, x.want)
+		}
+	}
+}
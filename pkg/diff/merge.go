@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"strings"
+)
+
+const (
+	conflictStart     = "<<<<<<< "
+	conflictSeparator = "=======\n"
+	conflictEnd       = ">>>>>>> "
+)
+
+//Merge three-way merges the changes base => ours and base => theirs line by line, like git merge-file.
+//Chunks changed on one side only take that side, chunks changed the same way on both sides are taken once and
+//chunks changed differently are written between conflict markers labeled oursName and theirsName.
+//It returns the merged content and the number of conflicts
+func Merge(base, ours, theirs []byte, oursName, theirsName string) ([]byte, int) {
+	baseLines, ourLines, theirLines := SplitLines(base), SplitLines(ours), SplitLines(theirs)
+	ourMatch := matches(baseLines, ourLines)
+	theirMatch := matches(baseLines, theirLines)
+
+	var out strings.Builder
+	conflicts := 0
+	i, j, k := 0, 0, 0
+	for i < len(baseLines) || j < len(ourLines) || k < len(theirLines) {
+		if i < len(baseLines) && ourMatch[i] == j && theirMatch[i] == k {
+			//stable line, unchanged on both sides
+			out.WriteString(baseLines[i])
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+		//the unstable chunk ends at the next base line both sides kept
+		end, ourEnd, theirEnd := i, len(ourLines), len(theirLines)
+		for ; end < len(baseLines); end++ {
+			if ourMatch[end] >= j && theirMatch[end] >= k {
+				ourEnd, theirEnd = ourMatch[end], theirMatch[end]
+				break
+			}
+		}
+		baseChunk, ourChunk, theirChunk := baseLines[i:end], ourLines[j:ourEnd], theirLines[k:theirEnd]
+		switch {
+		case equal(ourChunk, baseChunk):
+			writeLines(&out, theirChunk)
+		case equal(theirChunk, baseChunk), equal(ourChunk, theirChunk):
+			writeLines(&out, ourChunk)
+		default:
+			conflicts++
+			out.WriteString(conflictStart + oursName + "\n")
+			writeLines(&out, ourChunk)
+			endLine(&out)
+			out.WriteString(conflictSeparator)
+			writeLines(&out, theirChunk)
+			endLine(&out)
+			out.WriteString(conflictEnd + theirsName + "\n")
+		}
+		i, j, k = end, ourEnd, theirEnd
+	}
+	return []byte(out.String()), conflicts
+}
+
+//matches index of the line in b every line of a is matched with in their longest common subsequence, -1 if none
+func matches(a, b []string) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	for _, x := range Lines(a, b) {
+		if x.Kind == OpEqual {
+			match[x.OldIndex] = x.NewIndex
+		}
+	}
+	return match
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, x := range lines {
+		out.WriteString(x)
+	}
+}
+
+//endLine terminates a last line without a line ending so conflict markers start on a line of their own
+func endLine(out *strings.Builder) {
+	if s := out.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		out.WriteString("\n")
+	}
+}
@@ -0,0 +1,170 @@
+package diff
+
+import (
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		base          string
+		ours          string
+		theirs        string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "unchanged",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\n",
+			want:   "a\nb\nc\n",
+		},
+		{
+			name:   "changed on our side",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nb\nc\n",
+			want:   "a\nB\nc\n",
+		},
+		{
+			name:   "changed on their side",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nC\n",
+			want:   "a\nb\nC\n",
+		},
+		{
+			name:   "same change on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nB\nc\n",
+			want:   "a\nB\nc\n",
+		},
+		{
+			name:   "changes far apart",
+			base:   "a\nb\nc\nd\ne\n",
+			ours:   "A\nb\nc\nd\ne\n",
+			theirs: "a\nb\nc\nd\nE\n",
+			want:   "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:   "inserts at different spots",
+			base:   "a\nb\nc\n",
+			ours:   "a\nours\nb\nc\n",
+			theirs: "a\nb\ntheirs\nc\n",
+			want:   "a\nours\nb\ntheirs\nc\n",
+		},
+		{
+			name:          "conflicting inserts at the same spot",
+			base:          "a\nc\n",
+			ours:          "a\nours\nc\n",
+			theirs:        "a\ntheirs\nc\n",
+			want:          "a\n<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\nc\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "conflicting changes of the same line",
+			base:          "a\nb\nc\n",
+			ours:          "a\nours\nc\n",
+			theirs:        "a\ntheirs\nc\n",
+			want:          "a\n<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\nc\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "two conflicts",
+			base:          "a\nb\nc\nd\ne\n",
+			ours:          "1\nb\nc\nd\n5\n",
+			theirs:        "one\nb\nc\nd\nfive\n",
+			want:          "<<<<<<< ours\n1\n=======\none\n>>>>>>> theirs\nb\nc\nd\n<<<<<<< ours\n5\n=======\nfive\n>>>>>>> theirs\n",
+			wantConflicts: 2,
+		},
+		{
+			name:   "line deleted on our side",
+			base:   "a\nb\nc\n",
+			ours:   "a\nc\n",
+			theirs: "a\nb\nc\n",
+			want:   "a\nc\n",
+		},
+		{
+			name:   "line deleted on their side",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\n",
+			want:   "a\nb\n",
+		},
+		{
+			name:   "line deleted on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nc\n",
+			theirs: "a\nc\n",
+			want:   "a\nc\n",
+		},
+		{
+			name:          "line deleted on one side and changed on the other",
+			base:          "a\nb\nc\n",
+			ours:          "a\nc\n",
+			theirs:        "a\nB\nc\n",
+			want:          "a\n<<<<<<< ours\n=======\nB\n>>>>>>> theirs\nc\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "changes of content without a final newline",
+			base:   "a\nb\nc",
+			ours:   "A\nb\nc",
+			theirs: "a\nb\nC",
+			want:   "A\nb\nC",
+		},
+		{
+			//like git merge-file, changes of adjacent lines touch the same chunk
+			name:          "changes of adjacent lines",
+			base:          "a\nb\n",
+			ours:          "A\nb\n",
+			theirs:        "a\nB\n",
+			want:          "<<<<<<< ours\nA\nb\n=======\na\nB\n>>>>>>> theirs\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "final newline added on one side",
+			base:   "a\nb",
+			ours:   "a\nb",
+			theirs: "a\nb\n",
+			want:   "a\nb\n",
+		},
+		{
+			name:          "no final newline conflict",
+			base:          "a\nb",
+			ours:          "a\nours",
+			theirs:        "a\ntheirs",
+			want:          "a\n<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\n",
+			wantConflicts: 1,
+		},
+		{
+			//without a base no line is stable, the whole content conflicts
+			name:          "no base",
+			base:          "",
+			ours:          "a\nours\n",
+			theirs:        "a\ntheirs\n",
+			want:          "<<<<<<< ours\na\nours\n=======\na\ntheirs\n>>>>>>> theirs\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "no base, same content",
+			base:   "",
+			ours:   "a\n",
+			theirs: "a\n",
+			want:   "a\n",
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			got, conflicts := Merge([]byte(x.base), []byte(x.ours), []byte(x.theirs), "ours", "theirs")
+			if string(got) != x.want {
+				t.Errorf("merged\n%s\nwant\n%s", got, x.want)
+			}
+			if conflicts != x.wantConflicts {
+				t.Errorf("conflicts = %d, want %d", conflicts, x.wantConflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/AkronimBlack/stock/common"
+	"github.com/AkronimBlack/stock/pkg/goedit"
+	"github.com/AkronimBlack/stock/pkg/schema"
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+)
+
+//goTypes field types accepted in --fields and the go type they map to
+var goTypes = map[string]string{
+	"string":    "string",
+	"text":      "string",
+	"uuid":      "string",
+	"int":       "int",
+	"integer":   "int",
+	"int64":     "int64",
+	"bigint":    "int64",
+	"float":     "float64",
+	"double":    "float64",
+	"decimal":   "float64",
+	"bool":      "bool",
+	"boolean":   "bool",
+	"time":      "time.Time",
+	"date":      "time.Time",
+	"datetime":  "time.Time",
+	"timestamp": "time.Time",
+}
+
+//reservedVars names that would shadow a keyword or a package imported by the generated files
+var reservedVars = map[string]bool{
+	"application": true, "context": true, "domain": true, "errors": true, "http": true,
+	"json": true, "repositories": true, "strconv": true, "sync": true, "time": true,
+}
+
+//Field a single entity field parsed from name:type
+type Field struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	GoType string `json:"go_type"`
+}
+
+//Numeric reports if the field is an integer, numeric ids are assigned by the repository
+func (f *Field) Numeric() bool {
+	return f.GoType == "int" || f.GoType == "int64"
+}
+
+//ParseFields parses a field list in the form "id:uuid,total:decimal,status:string".
+//A field without a type is a string. An id field of type uuid is added when there is none
+func ParseFields(spec string) ([]*Field, error) {
+	var fields []*Field
+	seen := map[string]bool{}
+	for _, x := range strings.Split(spec, ",") {
+		x = strings.TrimSpace(x)
+		if x == "" {
+			continue
+		}
+		parts := strings.SplitN(x, ":", 2)
+		field := &Field{Name: strings.TrimSpace(parts[0]), Type: "string"}
+		if len(parts) == 2 {
+			field.Type = strings.ToLower(strings.TrimSpace(parts[1]))
+		}
+		if len(common.SplitWords(field.Name)) == 0 {
+			return nil, fmt.Errorf("invalid field name %q", field.Name)
+		}
+		var ok bool
+		if field.GoType, ok = goTypes[field.Type]; !ok {
+			return nil, fmt.Errorf("field %s has unknown type %s, available: %s", field.Name, field.Type, strings.Join(Types(), ", "))
+		}
+		key := templates.GoName(field.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("field %s is defined twice", field.Name)
+		}
+		seen[key] = true
+		fields = append(fields, field)
+	}
+	if !seen["ID"] {
+		fields = append([]*Field{{Name: "id", Type: "uuid", GoType: "string"}}, fields...)
+	}
+	return fields, nil
+}
+
+//Types list of field types accepted by ParseFields
+func Types() []string {
+	list := make([]string, 0, len(goTypes))
+	for x := range goTypes {
+		list = append(list, x)
+	}
+	sort.Strings(list)
+	return list
+}
+
+//Entity template data for every file generated for an entity
+type Entity struct {
+	Name      string   `json:"name"`
+	Fields    []*Field `json:"fields"`
+	Module    string   `json:"module"`
+	Framework string   `json:"framework"`
+	//Database the project database, the entity is stored in it
+	Database string `json:"database"`
+	//model of the sql repository, nil unless the project database is used through database/sql
+	model *schema.Model
+}
+
+//New entity in project. The name is turned into a go identifier (order_item => OrderItem)
+func New(name string, fields []*Field, project *wizard.Project) (*Entity, error) {
+	if len(common.SplitWords(name)) == 0 {
+		return nil, fmt.Errorf("invalid entity name %q", name)
+	}
+	e := &Entity{
+		Name:      templates.GoName(name),
+		Fields:    fields,
+		Module:    project.ModulePath,
+		Framework: project.Framework,
+		Database:  project.Database,
+	}
+	id := e.ID()
+	if id == nil {
+		return nil, fmt.Errorf("entity %s has no id field", e.Name)
+	}
+	if id.GoType != "string" && !id.Numeric() {
+		return nil, fmt.Errorf("id of %s must be a string, uuid or integer, got %s", e.Name, id.Type)
+	}
+	db := wizard.DatabaseByName(e.Database)
+	switch {
+	case db == nil:
+	case db.SQL():
+		model, err := e.sqlModel()
+		if err != nil {
+			return nil, err
+		}
+		if model.Queries.Update == "" {
+			return nil, fmt.Errorf("entity %s needs a field besides id to be stored in %s", e.Name, e.Database)
+		}
+		e.model = model
+	case db.Mongo() && id.Numeric():
+		return nil, fmt.Errorf("id of %s must be a string or uuid to be stored in %s, got %s", e.Name, e.Database, id.Type)
+	}
+	return e, nil
+}
+
+//sqlModel the entity as a row of its table, numeric ids are generated by the database
+func (e *Entity) sqlModel() (*schema.Model, error) {
+	fields := make([]*schema.Field, 0, len(e.Fields))
+	for _, x := range e.Fields {
+		fields = append(fields, &schema.Field{
+			Name:      templates.GoName(x.Name),
+			Column:    templates.Snake(x.Name),
+			GoType:    x.GoType,
+			Generated: x == e.ID() && x.Numeric(),
+		})
+	}
+	return schema.NewFieldModel(e.Name, e.Table(), e.Module, e.Database, fields, []string{templates.Snake(e.ID().Name)})
+}
+
+//Table name of the table or collection the entity is stored in (OrderItem => order_items)
+func (e *Entity) Table() string {
+	return templates.Plural(templates.Snake(e.Name))
+}
+
+//Mongo reports if the entity is stored in mongodb, used by templates
+func (e *Entity) Mongo() bool {
+	db := wizard.DatabaseByName(e.Database)
+	return db != nil && db.Mongo()
+}
+
+//ID the id field
+func (e *Entity) ID() *Field {
+	for _, x := range e.Fields {
+		if templates.GoName(x.Name) == "ID" {
+			return x
+		}
+	}
+	return nil
+}
+
+//HasTime reports if any field is a time.Time
+func (e *Entity) HasTime() bool {
+	for _, x := range e.Fields {
+		if x.GoType == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+//Var name for a local variable holding the entity, never a keyword or an imported package
+func (e *Entity) Var() string {
+	name := templates.Camel(e.Name)
+	if token.IsKeyword(name) || reservedVars[name] {
+		name += "Item"
+	}
+	return name
+}
+
+//Objects files generated for the entity, relative to the project root.
+//The repository is backed by the project database, in memory when the project has none
+func (e *Entity) Objects(framework *wizard.Framework) []*wizard.Object {
+	repository := &wizard.Object{
+		Name:     "infrastructure/repositories/{{.Name | snake}}_repository.go",
+		Type:     wizard.TypeFile,
+		Template: templates.Loader("entity/memory_repository.go"),
+	}
+	switch {
+	case e.model != nil:
+		repository = &wizard.Object{
+			Name:     "infrastructure/repositories/{{.Name | snake}}_sql_repository.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("repo/sql_repository.go"),
+			Data:     e.model,
+		}
+	case e.Mongo():
+		repository = &wizard.Object{
+			Name:     "infrastructure/repositories/{{.Name | snake}}_mongo_repository.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("entity/mongo_repository.go"),
+		}
+	}
+	return []*wizard.Object{
+		{
+			Name:     "domain/{{.Name | snake}}.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("entity/domain.go"),
+		},
+		{
+			Name:     "domain/{{.Name | snake}}_repository.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("entity/repository.go"),
+		},
+		{
+			Name:     "application/{{.Name | snake}}_service.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("entity/service.go"),
+		},
+		repository,
+		{
+			Name:     "infrastructure/transport/http/{{.Name | snake}}_handler.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader(framework.HandlerTemplate),
+		},
+	}
+}
+
+//NewConfig generator config writing the entity files into the project directory
+func NewConfig(project *wizard.Project, e *Entity, conflictPolicy string) *wizard.Config {
+	config := wizard.NewConfig(e.Name, project.ModulePath, project.Names.Maintainer, e)
+	config.OutputDir = project.Dir
+	config.ConflictPolicy = conflictPolicy
+	return config
+}
+
+//handlerVar package level variable in main.go holding the entity handlers
+func (e *Entity) handlerVar() string {
+	return e.Var() + "Handler"
+}
+
+//wiring what main.go needs to build the entity handlers in buildDependencies() and register their routes in
+//httpRouter(), both automatic registration and the code printed for --no-register are made from it
+type wiring struct {
+	//imports name and path of the imported packages, name is empty when the package name is used
+	imports [][2]string
+	//handlerVar and handlerType package level variable holding the handlers
+	handlerVar  string
+	handlerType string
+	build       string
+	route       string
+}
+
+func (e *Entity) wiring() *wiring {
+	repository := fmt.Sprintf("repositories.New%sMemoryRepository()", e.Name)
+	switch {
+	case e.model != nil:
+		repository = fmt.Sprintf("repositories.New%sSQLRepository(db)", e.Name)
+	case e.Mongo():
+		repository = fmt.Sprintf("repositories.New%sMongoRepository(db)", e.Name)
+	}
+	return &wiring{
+		imports: [][2]string{
+			{"", path.Join(e.Module, "application")},
+			{"", path.Join(e.Module, "infrastructure/repositories")},
+			{"httptransport", path.Join(e.Module, "infrastructure/transport/http")},
+		},
+		handlerVar:  e.handlerVar(),
+		handlerType: "*httptransport." + e.Name + "Handler",
+		build:       fmt.Sprintf("%s = httptransport.New%sHandler(application.New%sService(%s))", e.handlerVar(), e.Name, e.Name, repository),
+		route:       fmt.Sprintf("httptransport.Register%sRoutes(router, %s)", e.Name, e.handlerVar()),
+	}
+}
+
+//Registration go code wiring the entity handlers into main.go, what RouterObject adds
+func (e *Entity) Registration() string {
+	w := e.wiring()
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, x := range w.imports {
+		if x[0] != "" {
+			b.WriteString("\t" + x[0] + " ")
+		} else {
+			b.WriteString("\t")
+		}
+		b.WriteString(fmt.Sprintf("%q\n", x[1]))
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("var " + w.handlerVar + " " + w.handlerType + "\n\n")
+	b.WriteString("//in buildDependencies()\n" + w.build + "\n\n")
+	b.WriteString("//in httpRouter()\n" + w.route + "\n")
+	return b.String()
+}
+
+//RouterObject edits mainFile so the entity handlers are built in buildDependencies() and registered in httpRouter()
+func (e *Entity) RouterObject(mainFile string) *wizard.Object {
+	return &wizard.Object{
+		Name: mainFile,
+		Type: wizard.TypeFile,
+		Edit: e.register,
+	}
+}
+
+func (e *Entity) register(src []byte) ([]byte, error) {
+	file, err := goedit.Parse("main.go", src)
+	if err != nil {
+		return nil, err
+	}
+	w := e.wiring()
+	for _, x := range w.imports {
+		if err = file.AddImport(x[0], x[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err = file.AddVar(w.handlerVar, w.handlerType); err != nil {
+		return nil, err
+	}
+	err = file.AddStatement("buildDependencies", w.build)
+	if err == nil {
+		err = file.AddStatement("httpRouter", w.route)
+	}
+	if errors.Is(err, goedit.ErrNotFound) {
+		return nil, fmt.Errorf("%w, use --no-register and register the routes by hand", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file.Bytes()
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AkronimBlack/stock/pkg/wizard"
+)
+
+const mainSource = `package main
+
+import "net/http"
+
+var server *http.Server
+
+func buildDependencies() {
+}
+
+func httpRouter() http.Handler {
+	router := http.NewServeMux()
+	return router
+}
+`
+
+func TestRegistration(t *testing.T) {
+	tests := []struct {
+		database   string
+		repository string
+	}{
+		{database: "none", repository: "repositories.NewOrderMemoryRepository()"},
+		{database: "postgres", repository: "repositories.NewOrderSQLRepository(db)"},
+		{database: "sqlite", repository: "repositories.NewOrderSQLRepository(db)"},
+		{database: "mongodb", repository: "repositories.NewOrderMongoRepository(db)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.database, func(t *testing.T) {
+			fields, err := ParseFields("total:decimal")
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := New("order", fields, &wizard.Project{ModulePath: "example.com/acme/shop", Framework: "chi", Database: tt.database})
+			if err != nil {
+				t.Fatal(err)
+			}
+			registration := e.Registration()
+			edited, err := e.register([]byte(mainSource))
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := e.wiring()
+			for _, x := range []string{w.build, w.route, w.handlerVar + " " + w.handlerType, `httptransport "example.com/acme/shop/infrastructure/transport/http"`} {
+				if !strings.Contains(registration, x) {
+					t.Errorf("registration has no %q:\n%s", x, registration)
+				}
+				if !strings.Contains(strings.Join(strings.Fields(string(edited)), " "), x) {
+					t.Errorf("main.go has no %q:\n%s", x, edited)
+				}
+			}
+			if !strings.Contains(w.build, tt.repository) {
+				t.Errorf("build = %q, want %s", w.build, tt.repository)
+			}
+		})
+	}
+}
+
+func TestNewDatabaseErrors(t *testing.T) {
+	tests := []struct {
+		database string
+		fields   string
+	}{
+		{database: "postgres", fields: "id:int"},
+		{database: "mongodb", fields: "id:int,total:decimal"},
+	}
+	for _, tt := range tests {
+		fields, err := ParseFields(tt.fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = New("order", fields, &wizard.Project{ModulePath: "example.com/acme/shop", Database: tt.database}); err == nil {
+			t.Errorf("%s %s: want an error", tt.database, tt.fields)
+		}
+	}
+}
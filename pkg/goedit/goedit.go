@@ -0,0 +1,373 @@
+package goedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+//ErrNotFound returned when an edit targets a function that is not declared in the file
+var ErrNotFound = errors.New("not found")
+
+//File a go source file edited through its syntax tree. Edits insert source text at positions taken from the tree,
+//so user code and comments are kept as they are. Every edit is a no-op when its result is already in the file
+type File struct {
+	name string
+	src  []byte
+	fset *token.FileSet
+	file *ast.File
+}
+
+//Parse parses src, name is only used in error messages
+func Parse(name string, src []byte) (*File, error) {
+	f := &File{name: name}
+	if err := f.reparse(src); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) reparse(src []byte) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.name, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	f.src, f.fset, f.file = src, fset, file
+	return nil
+}
+
+//Bytes the edited source, gofmt'd
+func (f *File) Bytes() ([]byte, error) {
+	return format.Source(f.src)
+}
+
+//offset byte offset of pos in src
+func (f *File) offset(pos token.Pos) int {
+	return f.fset.Position(pos).Offset
+}
+
+//lineEnd offset of the end of the line pos is on, so a trailing comment stays with the code before it.
+//When other code follows on the same line the offset of pos itself is returned
+func (f *File) lineEnd(pos token.Pos) int {
+	offset := f.offset(pos)
+	rest := f.src[offset:]
+	if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	tail := strings.TrimSpace(string(rest))
+	if tail == "" || strings.HasPrefix(tail, "//") {
+		return offset + len(rest)
+	}
+	return offset
+}
+
+//insert adds text at offset and parses the result, src is left untouched if the result is not valid go
+func (f *File) insert(offset int, text string) error {
+	return f.replace(offset, offset, text)
+}
+
+//replace replaces src[start:end] with text and parses the result, src is left untouched if the result is not valid go
+func (f *File) replace(start, end int, text string) error {
+	src := make([]byte, 0, len(f.src)+len(text))
+	src = append(src, f.src[:start]...)
+	src = append(src, text...)
+	src = append(src, f.src[end:]...)
+	if err := f.reparse(src); err != nil {
+		return fmt.Errorf("%s: inserting %q: %w", f.name, strings.TrimSpace(text), err)
+	}
+	return nil
+}
+
+//AddImport imports path, under name if it is not empty
+func (f *File) AddImport(name, path string) error {
+	for _, x := range f.file.Imports {
+		existing, err := strconv.Unquote(x.Path.Value)
+		if err != nil || existing != path {
+			continue
+		}
+		if name != "" && (x.Name == nil || x.Name.Name != name) {
+			return fmt.Errorf("%s: %s is already imported under another name", f.name, path)
+		}
+		return nil
+	}
+	spec := strconv.Quote(path)
+	if name != "" {
+		spec = name + " " + spec
+	}
+
+	var last *ast.GenDecl
+	for _, x := range f.file.Decls {
+		if decl, ok := x.(*ast.GenDecl); ok && decl.Tok == token.IMPORT {
+			last = decl
+		}
+	}
+	switch {
+	case last == nil:
+		return f.insert(f.offset(f.file.Name.End()), "\n\nimport "+spec+"\n")
+	case last.Lparen.IsValid():
+		return f.insertInBlock(last, spec)
+	}
+	//a single import becomes a block holding both
+	existing := string(f.src[f.offset(last.Specs[0].Pos()):f.lineEnd(last.End())])
+	return f.replace(f.offset(last.Pos()), f.lineEnd(last.End()), "import (\n\t"+existing+"\n\t"+spec+"\n)")
+}
+
+//AddVar declares a package level variable, inside the first var ( ... ) block when there is one
+func (f *File) AddVar(name, typ string) error {
+	var block, lastImport *ast.GenDecl
+	for _, x := range f.file.Decls {
+		decl, ok := x.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		if decl.Tok == token.IMPORT {
+			lastImport = decl
+		}
+		if decl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			for _, x := range spec.(*ast.ValueSpec).Names {
+				if x.Name == name {
+					return nil
+				}
+			}
+		}
+		if block == nil && decl.Lparen.IsValid() {
+			block = decl
+		}
+	}
+	switch {
+	case block != nil:
+		return f.insertInBlock(block, name+" "+typ)
+	case lastImport != nil:
+		return f.insert(f.offset(lastImport.End()), "\n\nvar "+name+" "+typ+"\n")
+	}
+	return f.insert(f.offset(f.file.Name.End()), "\n\nvar "+name+" "+typ+"\n")
+}
+
+//insertInBlock adds spec on its own line right after the last spec of a parenthesized declaration
+func (f *File) insertInBlock(decl *ast.GenDecl, spec string) error {
+	if n := len(decl.Specs); n > 0 {
+		return f.insert(f.lineEnd(decl.Specs[n-1].End()), "\n\t"+spec)
+	}
+	return f.insert(f.offset(decl.Rparen), "\n\t"+spec+"\n")
+}
+
+//AddStatement appends stmt to the body of the function funcName, before its final return if it ends in one.
+//A statement that is already in the body, ignoring formatting, is not added again
+func (f *File) AddStatement(funcName, stmt string) error {
+	normalized, err := normalizeStatement(stmt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.name, err)
+	}
+	fn := f.function(funcName)
+	if fn == nil || fn.Body == nil {
+		return fmt.Errorf("%s: function %s: %w", f.name, funcName, ErrNotFound)
+	}
+	for _, x := range fn.Body.List {
+		if f.normalize(x) == normalized {
+			return nil
+		}
+	}
+	n := len(fn.Body.List)
+	if n == 0 {
+		return f.insert(f.offset(fn.Body.Lbrace)+1, "\n"+stmt)
+	}
+	if ret, ok := fn.Body.List[n-1].(*ast.ReturnStmt); ok {
+		return f.insert(f.offset(ret.Pos()), stmt+"\n")
+	}
+	return f.insert(f.lineEnd(fn.Body.List[n-1].End()), "\n"+stmt)
+}
+
+//function top level function declaration, methods are ignored
+func (f *File) function(name string) *ast.FuncDecl {
+	for _, x := range f.file.Decls {
+		if fn, ok := x.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+func (f *File) normalize(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, f.fset, node); err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+//normalizeStatement checks that stmt is a single go statement and returns it the way normalize prints it
+func normalizeStatement(stmt string) (string, error) {
+	src := "package p\nfunc _() {\n" + stmt + "\n}\n"
+	tmp := &File{name: "statement"}
+	if err := tmp.reparse([]byte(src)); err != nil {
+		return "", fmt.Errorf("invalid statement %q: %w", stmt, err)
+	}
+	body := tmp.function("_").Body.List
+	if len(body) != 1 {
+		return "", fmt.Errorf("expected a single statement, got %q", stmt)
+	}
+	return tmp.normalize(body[0]), nil
+}
+
+//MergeFuncs merges the functions and methods of src into f. Those missing in f are appended with their doc comment,
+//those that exist get the signature from src when their parameter or result types differ. Bodies in f are never changed.
+//Imports of src are added when the merged code uses them
+func (f *File) MergeFuncs(src []byte) error {
+	generated, err := Parse(f.name, src)
+	if err != nil {
+		return err
+	}
+	var merged []string
+	for _, x := range generated.file.Decls {
+		fn, ok := x.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		existing := f.method(receiver(fn), fn.Name.Name)
+		if existing == nil {
+			start := fn.Pos()
+			if fn.Doc != nil {
+				start = fn.Doc.Pos()
+			}
+			text := string(generated.src[generated.offset(start):generated.offset(fn.End())])
+			if err = f.insert(len(f.src), "\n"+text+"\n"); err != nil {
+				return err
+			}
+			merged = append(merged, text)
+			continue
+		}
+		if f.signature(existing.Type) == generated.signature(fn.Type) {
+			continue
+		}
+		text := string(generated.src[generated.offset(fn.Pos()):generated.offset(fn.Body.Lbrace)])
+		start, end := f.offset(existing.Pos()), f.offset(existing.Body.Lbrace)
+		updated := append(append(append([]byte{}, f.src[:start]...), text...), f.src[end:]...)
+		if err = f.reparse(updated); err != nil {
+			return fmt.Errorf("%s: updating %s: %w", f.name, fn.Name.Name, err)
+		}
+		merged = append(merged, text)
+	}
+	code := strings.Join(merged, "\n")
+	for _, x := range generated.file.Imports {
+		path, err := strconv.Unquote(x.Path.Value)
+		if err != nil {
+			return err
+		}
+		name := ""
+		if x.Name != nil {
+			name = x.Name.Name
+		}
+		used := name
+		if used == "" {
+			used = path[strings.LastIndex(path, "/")+1:]
+		}
+		if !strings.Contains(code, used+".") {
+			continue
+		}
+		if err = f.AddImport(name, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//CommentOutStale comments out the exported methods of the receivers declared in src that src no longer has,
+//eg. handler methods of operations that were removed, so the code they reference going away does not break the build.
+//The commented out code is preceded by the line //note and the methods are returned as Receiver.Method.
+//Unexported methods are helpers of the user and are kept
+func (f *File) CommentOutStale(src []byte, note string) ([]string, error) {
+	generated, err := Parse(f.name, src)
+	if err != nil {
+		return nil, err
+	}
+	receivers := map[string]bool{}
+	for _, x := range generated.file.Decls {
+		if fn, ok := x.(*ast.FuncDecl); ok && receiver(fn) != "" {
+			receivers[receiver(fn)] = true
+		}
+	}
+	var stale []*ast.FuncDecl
+	for _, x := range f.file.Decls {
+		fn, ok := x.(*ast.FuncDecl)
+		if !ok || !receivers[receiver(fn)] || !fn.Name.IsExported() || generated.method(receiver(fn), fn.Name.Name) != nil {
+			continue
+		}
+		stale = append(stale, fn)
+	}
+	names := make([]string, len(stale))
+	//from the last one back, so the offsets of the others stay valid
+	for i := len(stale) - 1; i >= 0; i-- {
+		fn := stale[i]
+		names[i] = receiver(fn) + "." + fn.Name.Name
+		start := f.offset(fn.Pos())
+		if fn.Doc != nil {
+			start = f.offset(fn.Doc.Pos())
+		}
+		end := f.offset(fn.End())
+		lines := strings.Split(string(f.src[start:end]), "\n")
+		for j, line := range lines {
+			lines[j] = "//" + line
+		}
+		if err = f.replace(start, end, "//"+note+"\n"+strings.Join(lines, "\n")); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+//method top level function (recv empty) or method of recv
+func (f *File) method(recv, name string) *ast.FuncDecl {
+	for _, x := range f.file.Decls {
+		if fn, ok := x.(*ast.FuncDecl); ok && fn.Name.Name == name && receiver(fn) == recv {
+			return fn
+		}
+	}
+	return nil
+}
+
+//receiver type name of a method without the pointer, empty for functions
+func receiver(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+//signature parameter and result types of a function, names are ignored
+func (f *File) signature(t *ast.FuncType) string {
+	types := func(list *ast.FieldList) string {
+		if list == nil {
+			return ""
+		}
+		var result []string
+		for _, x := range list.List {
+			n := len(x.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				result = append(result, f.normalize(x.Type))
+			}
+		}
+		return strings.Join(result, ",")
+	}
+	return "(" + types(t.Params) + ")(" + types(t.Results) + ")"
+}
@@ -0,0 +1,451 @@
+package goedit
+
+import (
+	"errors"
+	"testing"
+)
+
+//applyTwice applies edit, compares the result with want, then applies it again and expects no change
+func applyTwice(t *testing.T, src, want string, edit func(f *File) error) {
+	t.Helper()
+	f, err := Parse("main.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 2; i++ {
+		if err = edit(f); err != nil {
+			t.Fatalf("edit %d: %v", i, err)
+		}
+		got, err := f.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Fatalf("edit %d got:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func TestAddImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		importName string
+		path       string
+		src        string
+		want       string
+	}{
+		{
+			name: "block",
+			path: "strings",
+			src: `package main
+
+import (
+	"fmt" // printing
+	//os is needed for exit codes
+	"os"
+)
+`,
+			want: `package main
+
+import (
+	"fmt" // printing
+	//os is needed for exit codes
+	"os"
+	"strings"
+)
+`,
+		},
+		{
+			name:       "single import",
+			importName: "str",
+			path:       "strings",
+			src: `package main
+
+import "fmt"
+
+func main() {}
+`,
+			want: `package main
+
+import (
+	"fmt"
+	str "strings"
+)
+
+func main() {}
+`,
+		},
+		{
+			name: "single import with comment",
+			path: "strings",
+			src: `package main
+
+// fmt is the only import
+import "fmt" // printing
+
+func main() {}
+`,
+			want: `package main
+
+// fmt is the only import
+import (
+	"fmt" // printing
+	"strings"
+)
+
+func main() {}
+`,
+		},
+		{
+			name: "no imports",
+			path: "fmt",
+			src: `// Package main doc
+package main
+
+// main doc
+func main() {}
+`,
+			want: `// Package main doc
+package main
+
+import "fmt"
+
+// main doc
+func main() {}
+`,
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			applyTwice(t, x.src, x.want, func(f *File) error {
+				return f.AddImport(x.importName, x.path)
+			})
+		})
+	}
+}
+
+func TestAddImportOtherName(t *testing.T) {
+	f, err := Parse("main.go", []byte("package main\n\nimport str \"strings\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.AddImport("s", "strings"); err == nil {
+		t.Error("strings imported under a second name")
+	}
+}
+
+func TestAddVar(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "block",
+			src: `package main
+
+var (
+	router *Router // the router
+	//db is opened in main
+	db *DB
+)
+`,
+			want: `package main
+
+var (
+	router *Router // the router
+	//db is opened in main
+	db           *DB
+	orderHandler *Handler
+)
+`,
+		},
+		{
+			name: "after imports",
+			src: `package main
+
+import "fmt"
+
+func main() { fmt.Println() }
+`,
+			want: `package main
+
+import "fmt"
+
+var orderHandler *Handler
+
+func main() { fmt.Println() }
+`,
+		},
+		{
+			name: "no imports",
+			src: `package main
+
+func main() {}
+`,
+			want: `package main
+
+var orderHandler *Handler
+
+func main() {}
+`,
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			applyTwice(t, x.src, x.want, func(f *File) error {
+				return f.AddVar("orderHandler", "*Handler")
+			})
+		})
+	}
+}
+
+func TestAddStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   string
+		src  string
+		want string
+	}{
+		{
+			name: "before return",
+			fn:   "routes",
+			src: `package main
+
+func routes() *Router {
+	r := NewRouter()
+	//health check
+	r.Get("/health", health)
+	return r
+}
+`,
+			want: `package main
+
+func routes() *Router {
+	r := NewRouter()
+	//health check
+	r.Get("/health", health)
+	orderHandler.Register(r)
+	return r
+}
+`,
+		},
+		{
+			name: "after trailing comment",
+			fn:   "buildDependencies",
+			src: `package main
+
+func buildDependencies() {
+	db = connect() // opens the database
+}
+`,
+			want: `package main
+
+func buildDependencies() {
+	db = connect() // opens the database
+	orderHandler.Register(r)
+}
+`,
+		},
+		{
+			name: "empty body",
+			fn:   "buildDependencies",
+			src: `package main
+
+func buildDependencies() {
+}
+`,
+			want: `package main
+
+func buildDependencies() {
+	orderHandler.Register(r)
+}
+`,
+		},
+		{
+			name: "already there formatted differently",
+			fn:   "buildDependencies",
+			src: `package main
+
+func buildDependencies() {
+	orderHandler.Register( r )
+}
+`,
+			want: `package main
+
+func buildDependencies() {
+	orderHandler.Register(r)
+}
+`,
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			applyTwice(t, x.src, x.want, func(f *File) error {
+				return f.AddStatement(x.fn, "orderHandler.Register(r)")
+			})
+		})
+	}
+}
+
+func TestAddStatementErrors(t *testing.T) {
+	f, err := Parse("main.go", []byte("package main\n\nfunc main() {}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.AddStatement("routes", "x()"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing function: %v, want ErrNotFound", err)
+	}
+	if err = f.AddStatement("main", "x(); y()"); err == nil {
+		t.Error("two statements added")
+	}
+	if err = f.AddStatement("main", "x("); err == nil {
+		t.Error("invalid statement added")
+	}
+}
+
+func TestMergeFuncs(t *testing.T) {
+	existing := `package application
+
+import "context"
+
+// OrderHandler handles orders
+type OrderHandler struct{}
+
+// ListOrders lists orders, written by hand
+func (h *OrderHandler) ListOrders(ctx context.Context) ([]Order, error) {
+	//keep me
+	return h.list(ctx)
+}
+
+// ShowOrder shows an order
+func (h *OrderHandler) ShowOrder(ctx context.Context, id int) (*Order, error) {
+	return find(id) // custom lookup
+}
+`
+	generated := `package application
+
+import (
+	"context"
+	"errors"
+)
+
+// ListOrders GET /orders
+func (h *OrderHandler) ListOrders(ctx context.Context) ([]Order, error) {
+	return nil, errors.New("not implemented")
+}
+
+// ShowOrder GET /orders/{id}
+func (h *OrderHandler) ShowOrder(ctx context.Context, id string) (*Order, error) {
+	return nil, errors.New("not implemented")
+}
+
+// CreateOrder POST /orders
+func (h *OrderHandler) CreateOrder(ctx context.Context, body *Order) (*Order, error) {
+	return nil, errors.New("not implemented")
+}
+`
+	want := `package application
+
+import (
+	"context"
+	"errors"
+)
+
+// OrderHandler handles orders
+type OrderHandler struct{}
+
+// ListOrders lists orders, written by hand
+func (h *OrderHandler) ListOrders(ctx context.Context) ([]Order, error) {
+	//keep me
+	return h.list(ctx)
+}
+
+// ShowOrder shows an order
+func (h *OrderHandler) ShowOrder(ctx context.Context, id string) (*Order, error) {
+	return find(id) // custom lookup
+}
+
+// CreateOrder POST /orders
+func (h *OrderHandler) CreateOrder(ctx context.Context, body *Order) (*Order, error) {
+	return nil, errors.New("not implemented")
+}
+`
+	applyTwice(t, existing, want, func(f *File) error {
+		return f.MergeFuncs([]byte(generated))
+	})
+}
+
+func TestCommentOutStale(t *testing.T) {
+	existing := `package application
+
+import "context"
+
+// OrderHandler handles orders
+type OrderHandler struct{}
+
+// ListOrders lists orders
+func (h *OrderHandler) ListOrders(ctx context.Context, params ListOrdersParams) ([]Order, error) {
+	return h.list(ctx)
+}
+
+// ShowOrder shows an order
+func (h *OrderHandler) ShowOrder(ctx context.Context, params ShowOrderParams) (*Order, error) {
+	return find(params.ID)
+}
+
+func (h *OrderHandler) list(ctx context.Context) ([]Order, error) {
+	return nil, nil
+}
+
+// Helper is not a method of a generated receiver
+func (x *Other) Helper() {}
+`
+	generated := `package application
+
+import "context"
+
+// ListOrders GET /orders
+func (h *OrderHandler) ListOrders(ctx context.Context, params ListOrdersParams) ([]Order, error) {
+	return nil, ErrNotImplemented
+}
+`
+	want := `package application
+
+import "context"
+
+// OrderHandler handles orders
+type OrderHandler struct{}
+
+// ListOrders lists orders
+func (h *OrderHandler) ListOrders(ctx context.Context, params ListOrdersParams) ([]Order, error) {
+	return h.list(ctx)
+}
+
+// stale
+//// ShowOrder shows an order
+//func (h *OrderHandler) ShowOrder(ctx context.Context, params ShowOrderParams) (*Order, error) {
+//	return find(params.ID)
+//}
+
+func (h *OrderHandler) list(ctx context.Context) ([]Order, error) {
+	return nil, nil
+}
+
+// Helper is not a method of a generated receiver
+func (x *Other) Helper() {}
+`
+	var stale []string
+	applyTwice(t, existing, want, func(f *File) error {
+		names, err := f.CommentOutStale([]byte(generated), " stale")
+		if stale == nil {
+			stale = names
+		} else if len(names) != 0 {
+			t.Errorf("second run commented out %v", names)
+		}
+		return err
+	})
+	if len(stale) != 1 || stale[0] != "OrderHandler.ShowOrder" {
+		t.Errorf("stale = %v, want [OrderHandler.ShowOrder]", stale)
+	}
+}
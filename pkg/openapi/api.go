@@ -0,0 +1,478 @@
+package openapi
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+//applicationPackage qualifier of the generated types outside of the application package
+const applicationPackage = "application."
+
+//API template data for every file generated from a document
+type API struct {
+	//Name prefix of the generated handler, interface and route function, eg. Service for ServiceHandler
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	//Spec path of the document relative to the project root
+	Spec      string      `json:"spec"`
+	Module    string      `json:"module"`
+	Framework string      `json:"framework"`
+	Types     []*Type     `json:"types"`
+	Endpoints []*Endpoint `json:"endpoints"`
+	HasTime   bool        `json:"has_time"`
+}
+
+//Type a go type generated in application for a schema
+type Type struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc"`
+	//Alias the underlying type of schemas that are not objects, eg. []Order
+	Alias  string   `json:"alias,omitempty"`
+	Embeds []string `json:"embeds,omitempty"`
+	Fields []*Field `json:"fields,omitempty"`
+}
+
+type Field struct {
+	Name     string `json:"name"`
+	JSONName string `json:"json_name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Doc      string `json:"doc"`
+}
+
+//Endpoint a single method and path of the document
+type Endpoint struct {
+	Name    string   `json:"name"`
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Summary string   `json:"summary"`
+	Params  []*Param `json:"params"`
+	//Body go type of the json request body, empty when there is none
+	Body         string `json:"body,omitempty"`
+	BodyRequired bool   `json:"body_required"`
+	//Response go type of the json success response as seen from the application package, empty when there is none
+	Response string `json:"response,omitempty"`
+	//Returns results of the handler method as seen from the http transport package
+	Returns string `json:"returns"`
+	Status  int    `json:"status"`
+}
+
+//ColonPath path with :name parameters for gin and echo
+func (o *Endpoint) ColonPath() string {
+	return pathParam.ReplaceAllString(o.Path, ":$1")
+}
+
+//Pattern the part of the path before the first parameter, for http.ServeMux
+func (o *Endpoint) Pattern() string {
+	if i := strings.Index(o.Path, "{"); i >= 0 {
+		return o.Path[:i]
+	}
+	return o.Path
+}
+
+//Param a path, query or header parameter
+type Param struct {
+	Name     string `json:"name"`
+	GoName   string `json:"go_name"`
+	In       string `json:"in"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	explode  bool
+}
+
+//Array reports if the parameter is a list of values, eg. ?status=new&status=paid
+func (p *Param) Array() bool {
+	return strings.HasPrefix(p.Type, "[]")
+}
+
+//Parser name of the generated function converting a raw value, eg. parseInt64. Arrays are parsed value by value
+func (p *Param) Parser() string {
+	return "parse" + templates.Pascal(strings.TrimPrefix(p.Type, "[]"))
+}
+
+//Source go expression reading the raw value inside a generated serve function, the raw values for arrays.
+//Query arrays are repeated unless they are not exploded, path and header arrays are comma separated
+func (p *Param) Source() string {
+	switch {
+	case p.In == "path" && p.Array():
+		return fmt.Sprintf("splitValues([]string{pathParam(%q)})", p.Name)
+	case p.In == "path":
+		return fmt.Sprintf("pathParam(%q)", p.Name)
+	case p.In == "header" && p.Array():
+		return fmt.Sprintf("splitValues(r.Header.Values(%q))", p.Name)
+	case p.In == "header":
+		return fmt.Sprintf("r.Header.Get(%q)", p.Name)
+	case p.Array() && !p.explode:
+		return fmt.Sprintf("splitValues(r.URL.Query()[%q])", p.Name)
+	case p.Array():
+		return fmt.Sprintf("r.URL.Query()[%q]", p.Name)
+	}
+	return fmt.Sprintf("r.URL.Query().Get(%q)", p.Name)
+}
+
+//NewAPI builds the template data for doc. name prefixes the generated handler and routes,
+//spec is the path of the document relative to the project root
+func NewAPI(doc *Document, name, spec, module, framework string) (*API, error) {
+	api := &API{
+		Name:      templates.GoName(name),
+		Title:     doc.Info.Title,
+		Version:   doc.Info.Version,
+		Spec:      path.Clean(spec),
+		Module:    module,
+		Framework: framework,
+	}
+	if api.Name == "" {
+		return nil, fmt.Errorf("invalid api name %q", name)
+	}
+	b := &builder{doc: doc, api: api, names: map[string]bool{}}
+	for _, x := range doc.Components.Schemas.Keys {
+		b.names[templates.GoName(x)] = true
+	}
+	for _, x := range doc.Components.Schemas.Keys {
+		if err := b.namedType(templates.GoName(x), doc.Components.Schemas.Items[x]); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", x, err)
+		}
+	}
+	for _, p := range doc.Paths.Keys {
+		item := doc.Paths.Items[p]
+		if item == nil {
+			continue
+		}
+		methods, operations := item.operations()
+		for i, x := range operations {
+			op, err := b.operation(p, methods[i], item.Parameters, x)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", methods[i], p, err)
+			}
+			api.Endpoints = append(api.Endpoints, op)
+		}
+	}
+	return api, nil
+}
+
+type builder struct {
+	doc *Document
+	api *API
+	//names every generated type name, inline schemas get a unique one
+	names map[string]bool
+}
+
+//uniqueName name, or name with a number appended when a type with that name already exists
+func (b *builder) uniqueName(name string) string {
+	result := name
+	for i := 2; b.names[result]; i++ {
+		result = name + strconv.Itoa(i)
+	}
+	b.names[result] = true
+	return result
+}
+
+//namedType adds a type named name for schema
+func (b *builder) namedType(name string, schema *Schema) error {
+	if schema == nil {
+		schema = &Schema{}
+	}
+	t := &Type{Name: name, Doc: schema.Description}
+	schema.goType = name
+	//added before the fields so nested inline types follow their parent
+	b.api.Types = append(b.api.Types, t)
+	if !isObject(schema) {
+		alias, err := b.goType(name, schema)
+		if err != nil {
+			return err
+		}
+		t.Alias = alias
+		return nil
+	}
+	for _, x := range schema.AllOf {
+		if x.Ref == "" {
+			if err := b.fields(t, name, x); err != nil {
+				return err
+			}
+			continue
+		}
+		ref, err := schemaRef(x.Ref)
+		if err != nil {
+			return err
+		}
+		t.Embeds = append(t.Embeds, templates.GoName(ref))
+	}
+	return b.fields(t, name, schema)
+}
+
+func (b *builder) fields(t *Type, parent string, schema *Schema) error {
+	for _, x := range schema.Properties.Keys {
+		property := schema.Properties.Items[x]
+		if property == nil {
+			property = &Schema{}
+		}
+		field := &Field{
+			Name:     templates.GoName(x),
+			JSONName: x,
+			Required: schema.isRequired(x),
+			Doc:      property.Description,
+		}
+		var err error
+		if field.Type, err = b.goType(parent+field.Name, property); err != nil {
+			return fmt.Errorf("property %s: %w", x, err)
+		}
+		field.Type = nullable(field.Type, property)
+		t.Fields = append(t.Fields, field)
+	}
+	return nil
+}
+
+func isObject(schema *Schema) bool {
+	return schema.Ref == "" && (len(schema.Properties.Keys) > 0 || len(schema.AllOf) > 0)
+}
+
+//goType go type for schema as seen from the application package. Inline objects become new types named name
+func (b *builder) goType(name string, schema *Schema) (string, error) {
+	if schema == nil {
+		return "interface{}", nil
+	}
+	if schema.goType != "" && schema.goType != name {
+		return schema.goType, nil
+	}
+	if schema.Ref != "" {
+		ref, err := schemaRef(schema.Ref)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := b.doc.Components.Schemas.Items[ref]; !ok {
+			return "", fmt.Errorf("$ref %s does not exist", schema.Ref)
+		}
+		return templates.GoName(ref), nil
+	}
+	if isObject(schema) {
+		typeName := b.uniqueName(name)
+		return typeName, b.namedType(typeName, schema)
+	}
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" || schema.Format == "date" {
+			b.api.HasTime = true
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		switch schema.Format {
+		case "int64":
+			return "int64", nil
+		case "int32":
+			return "int32", nil
+		}
+		return "int", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		items, err := b.goType(name+"Item", schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + nullable(items, schema.Items), nil
+	case "object":
+		if schema.AdditionalProperties == nil || schema.AdditionalProperties.Schema == nil {
+			return "map[string]interface{}", nil
+		}
+		values, err := b.goType(name+"Value", schema.AdditionalProperties.Schema)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + nullable(values, schema.AdditionalProperties.Schema), nil
+	}
+	return "interface{}", nil
+}
+
+//nullable goType of a nullable schema, a pointer unless nil already is a value of the type
+func nullable(goType string, schema *Schema) string {
+	if schema == nil || !schema.Nullable || goType == "interface{}" || strings.HasPrefix(goType, "[]") ||
+		strings.HasPrefix(goType, "map[") || strings.HasPrefix(goType, "*") {
+		return goType
+	}
+	return "*" + goType
+}
+
+//qualify type as seen from outside the application package
+func (b *builder) qualify(goType string) string {
+	prefix := ""
+	for {
+		switch {
+		case strings.HasPrefix(goType, "[]"):
+			prefix += "[]"
+			goType = goType[2:]
+			continue
+		case strings.HasPrefix(goType, "map[string]"):
+			prefix += "map[string]"
+			goType = goType[len("map[string]"):]
+			continue
+		case strings.HasPrefix(goType, "*"):
+			prefix += "*"
+			goType = goType[1:]
+			continue
+		}
+		break
+	}
+	if b.names[goType] {
+		goType = applicationPackage + goType
+	}
+	return prefix + goType
+}
+
+func (b *builder) operation(p, method string, shared []*Parameter, x *Operation) (*Endpoint, error) {
+	op := &Endpoint{
+		Name:    templates.GoName(x.OperationID),
+		Method:  method,
+		Path:    p,
+		Summary: strings.TrimSpace(x.Summary),
+	}
+	if op.Name == "" {
+		op.Name = templates.GoName(strings.ToLower(method) + " " + pathParam.ReplaceAllString(p, "by $1"))
+	}
+	seen := map[string]bool{}
+	//operation parameters override the ones declared on the path
+	for _, list := range [][]*Parameter{x.Parameters, shared} {
+		for _, param := range list {
+			param, err := b.parameter(param)
+			if err != nil {
+				return nil, err
+			}
+			if param == nil || seen[param.In+param.Name] {
+				continue
+			}
+			seen[param.In+param.Name] = true
+			op.Params = append(op.Params, param)
+		}
+	}
+	sort.SliceStable(op.Params, func(i, j int) bool { return paramOrder[op.Params[i].In] < paramOrder[op.Params[j].In] })
+
+	if x.RequestBody != nil {
+		if x.RequestBody.Ref != "" {
+			return nil, fmt.Errorf("unsupported request body $ref %s", x.RequestBody.Ref)
+		}
+		if media := jsonContent(x.RequestBody.Content); media != nil {
+			body, err := b.goType(op.Name+"Request", media.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+			op.Body = body
+			op.BodyRequired = x.RequestBody.Required
+		}
+	}
+
+	op.Status = 200
+	if code, response := successResponse(x.Responses); response != nil {
+		op.Status = code
+		if response.Ref != "" {
+			return nil, fmt.Errorf("unsupported response $ref %s", response.Ref)
+		}
+		if media := jsonContent(response.Content); media != nil {
+			result, err := b.goType(op.Name+"Response", media.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("response: %w", err)
+			}
+			op.Response = result
+		}
+	}
+	op.Returns = "error"
+	if op.Response != "" {
+		returns := b.qualify(op.Response)
+		if !strings.HasPrefix(returns, "[]") && !strings.HasPrefix(returns, "map[") && !strings.HasPrefix(returns, "*") &&
+			returns != "interface{}" {
+			returns = "*" + returns
+		}
+		op.Returns = "(" + returns + ", error)"
+	}
+	return op, nil
+}
+
+var paramOrder = map[string]int{"path": 0, "query": 1, "header": 2}
+
+func (b *builder) parameter(x *Parameter) (*Param, error) {
+	if x.Ref != "" {
+		const prefix = "#/components/parameters/"
+		shared, ok := b.doc.Components.Parameters[strings.TrimPrefix(x.Ref, prefix)]
+		if !strings.HasPrefix(x.Ref, prefix) || !ok {
+			return nil, fmt.Errorf("unsupported parameter $ref %s", x.Ref)
+		}
+		x = shared
+	}
+	if _, ok := paramOrder[x.In]; !ok {
+		//cookie parameters are left to the handler
+		return nil, nil
+	}
+	param := &Param{
+		Name:     x.Name,
+		GoName:   templates.GoName(x.Name),
+		In:       x.In,
+		Type:     "string",
+		Required: x.Required || x.In == "path",
+		explode:  x.Explode == nil || *x.Explode,
+	}
+	schema := x.Schema
+	if schema != nil && schema.Type == "array" {
+		param.Type = "[]string"
+		schema = schema.Items
+	}
+	if schema != nil && schema.Ref == "" && !isObject(schema) {
+		switch t, _ := b.goType(param.GoName, schema); t {
+		case "int", "int32", "int64", "float32", "float64", "bool":
+			param.Type = strings.TrimSuffix(param.Type, "string") + t
+		}
+	}
+	return param, nil
+}
+
+//jsonContent the application/json media type, or any json one
+func jsonContent(content map[string]*MediaType) *MediaType {
+	if x, ok := content["application/json"]; ok {
+		return x
+	}
+	for name, x := range content {
+		if strings.HasSuffix(name, "+json") {
+			return x
+		}
+	}
+	return nil
+}
+
+//successResponse the lowest 2xx response
+func successResponse(responses map[string]*Response) (int, *Response) {
+	best := 0
+	for code := range responses {
+		if n, err := strconv.Atoi(code); err == nil && n >= 200 && n < 300 && (best == 0 || n < best) {
+			best = n
+		}
+	}
+	if best == 0 {
+		if x, ok := responses["2XX"]; ok {
+			return 200, x
+		}
+		return 0, nil
+	}
+	return best, responses[strconv.Itoa(best)]
+}
+
+//Patterns distinct http.ServeMux patterns of every endpoint, see Endpoint.Pattern
+func (a *API) Patterns() []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, x := range a.Endpoints {
+		if !seen[x.Pattern()] {
+			seen[x.Pattern()] = true
+			result = append(result, x.Pattern())
+		}
+	}
+	return result
+}
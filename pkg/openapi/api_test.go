@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+const typesDocument = `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets/{id}:
+    get:
+      operationId: showPet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, format: int64}}
+        - {name: status, in: query, schema: {type: array, items: {type: string}}}
+        - {name: ids, in: query, explode: false, schema: {type: array, items: {type: integer}}}
+        - {name: X-Tags, in: header, schema: {type: array, items: {type: boolean}}}
+        - {name: filter, in: query, schema: {type: object}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        nickname: {type: string, nullable: true}
+        owner: {$ref: '#/components/schemas/Owner'}
+        labels: {type: object, additionalProperties: {type: string}}
+        scores: {type: object, additionalProperties: {type: integer, nullable: true}}
+        owners: {type: object, additionalProperties: {$ref: '#/components/schemas/Owner'}}
+        extra: {type: object, additionalProperties: true}
+        tags: {type: array, nullable: true, items: {type: string, nullable: true}}
+    Owner:
+      type: object
+      properties:
+        name: {type: string}
+`
+
+func TestNewAPIParams(t *testing.T) {
+	doc, err := Parse([]byte(typesDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := NewAPI(doc, "pets", "pets.yaml", "example.com/acme/shop", "chi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, goType, parser, source string
+	}{
+		{"id", "int64", "parseInt64", `pathParam("id")`},
+		{"status", "[]string", "parseString", `r.URL.Query()["status"]`},
+		{"ids", "[]int", "parseInt", `splitValues(r.URL.Query()["ids"])`},
+		{"X-Tags", "[]bool", "parseBool", `splitValues(r.Header.Values("X-Tags"))`},
+		{"filter", "string", "parseString", `r.URL.Query().Get("filter")`},
+	}
+	params := map[string]*Param{}
+	for _, x := range api.Endpoints[0].Params {
+		params[x.Name] = x
+	}
+	for _, tt := range tests {
+		param := params[tt.name]
+		if param == nil {
+			t.Errorf("no parameter %s", tt.name)
+			continue
+		}
+		if param.Type != tt.goType || param.Parser() != tt.parser || param.Source() != tt.source {
+			t.Errorf("%s = %s %s %s, want %s %s %s", tt.name, param.Type, param.Parser(), param.Source(), tt.goType, tt.parser, tt.source)
+		}
+	}
+}
+
+func TestNewAPITypes(t *testing.T) {
+	doc, err := Parse([]byte(typesDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := NewAPI(doc, "pets", "pets.yaml", "example.com/acme/shop", "chi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Nickname": "*string",
+		"Owner":    "Owner",
+		"Labels":   "map[string]string",
+		"Scores":   "map[string]*int",
+		"Owners":   "map[string]Owner",
+		"Extra":    "map[string]interface{}",
+		"Tags":     "[]*string",
+	}
+	got := map[string]string{}
+	for _, x := range api.Types[0].Fields {
+		got[x.Name] = x.Type
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Pet fields = %v, want %v", got, want)
+	}
+	if returns := api.Endpoints[0].Returns; returns != "(*application.Pet, error)" {
+		t.Errorf("returns = %s", returns)
+	}
+}
@@ -0,0 +1,880 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+	"gopkg.in/yaml.v2"
+)
+
+//ExtractFrameworks frameworks whose route registrations Extract understands, none is net/http
+func ExtractFrameworks() []string {
+	return []string{"gin", "gorilla", "chi", "echo", "none"}
+}
+
+//route a route registration found in the project
+type route struct {
+	method  string
+	path    string
+	handler ast.Expr
+	env     *scope
+}
+
+type extractor struct {
+	*source
+	framework string
+	routes    []*route
+	//visited functions walked for routes, by function and router prefixes, so recursion ends
+	visited      map[string]bool
+	schemas      *schemaBuilder
+	operationIDs map[string]bool
+}
+
+//Extract builds an OpenAPI document from the routes a project registers in httpRouter() of its main.go.
+//Routes are found by following httpRouter() into every function it passes the router to, request and response
+//types by following each handler into the bind and json calls it makes. Nothing is compiled or run
+func Extract(project *wizard.Project, info Info) (*Document, error) {
+	if !isExtractFramework(project.Framework) {
+		return nil, fmt.Errorf("extracting routes is not supported for framework %s, supported: %s", project.Framework, strings.Join(ExtractFrameworks(), ", "))
+	}
+	if project.MainFile == "" {
+		return nil, fmt.Errorf("no main.go found in %s", project.Dir)
+	}
+	s := newSource(project.Dir, project.ModulePath)
+	x := &extractor{
+		source:       s,
+		framework:    project.Framework,
+		visited:      map[string]bool{},
+		schemas:      newSchemaBuilder(s),
+		operationIDs: map[string]bool{},
+	}
+	main := s.load(path.Join(project.ModulePath, path.Dir(filepath.ToSlash(project.MainFile))))
+	if main == nil || main.funcs["httpRouter"] == nil {
+		if err := s.err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s has no httpRouter() function", project.MainFile)
+	}
+	router := main.funcs["httpRouter"]
+	x.walk(router.body, funcScope(router), map[string]string{"router": ""})
+	if err := s.err(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   Paths{Items: map[string]*PathItem{}},
+	}
+	for _, r := range x.routes {
+		item, ok := doc.Paths.Items[r.path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths.Keys = append(doc.Paths.Keys, r.path)
+			doc.Paths.Items[r.path] = item
+		}
+		item.set(r.method, x.operation(r))
+	}
+	sort.Strings(doc.Paths.Keys)
+	doc.Components.Schemas = x.schemas.sorted()
+	return doc, nil
+}
+
+func isExtractFramework(name string) bool {
+	for _, x := range ExtractFrameworks() {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
+
+//set adds op for method, the first registration of a method wins as it does in every router
+func (p *PathItem) set(method string, op *Operation) {
+	targets := map[string]**Operation{
+		http.MethodGet:     &p.Get,
+		http.MethodPut:     &p.Put,
+		http.MethodPost:    &p.Post,
+		http.MethodDelete:  &p.Delete,
+		http.MethodPatch:   &p.Patch,
+		http.MethodHead:    &p.Head,
+		http.MethodOptions: &p.Options,
+	}
+	if target, ok := targets[method]; ok && *target == nil {
+		*target = op
+	}
+}
+
+//Marshal the document as yaml
+func (d *Document) Marshal() ([]byte, error) {
+	return yaml.Marshal(d)
+}
+
+//routerConstructors calls that create a router
+var routerConstructors = map[string]bool{
+	"gin.New":          true,
+	"gin.Default":      true,
+	"mux.NewRouter":    true,
+	"chi.NewRouter":    true,
+	"chi.NewMux":       true,
+	"echo.New":         true,
+	"http.NewServeMux": true,
+}
+
+//walk finds the routes registered in body. routers maps the variables holding a router to their path prefix
+func (x *extractor) walk(body *ast.BlockStmt, env *scope, routers map[string]string) {
+	if body == nil {
+		return
+	}
+	x.bind(env, body)
+	consumed := map[ast.Node]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if consumed[n] {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				if i >= len(node.Lhs) {
+					break
+				}
+				if id, ok := node.Lhs[i].(*ast.Ident); ok {
+					if prefix, ok := x.routerPrefix(rhs, routers); ok {
+						routers[id.Name] = prefix
+					}
+				}
+			}
+		case *ast.CallExpr:
+			x.call(node, env, routers, consumed)
+		case *ast.CompositeLit:
+			x.openAPIRoute(node, env)
+		}
+		return true
+	})
+}
+
+//routerPrefix the path prefix of a router expression, false if expr is not a router
+func (x *extractor) routerPrefix(expr ast.Expr, routers map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return x.routerPrefix(e.X, routers)
+	case *ast.Ident:
+		prefix, ok := routers[e.Name]
+		return prefix, ok
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && routerConstructors[id.Name+"."+sel.Sel.Name] {
+			return "", true
+		}
+		switch {
+		case sel.Sel.Name == "Group" && (x.framework == "gin" || x.framework == "echo") && len(e.Args) > 0:
+			//router.Group("/v1", middleware...)
+			if prefix, ok := x.routerPrefix(sel.X, routers); ok {
+				if p, ok := stringValue(e.Args[0]); ok {
+					return prefix + p, true
+				}
+			}
+		case sel.Sel.Name == "Subrouter" && x.framework == "gorilla":
+			//router.PathPrefix("/v1").Subrouter()
+			inner, ok := sel.X.(*ast.CallExpr)
+			if !ok || len(inner.Args) == 0 {
+				return "", false
+			}
+			if isel, ok := inner.Fun.(*ast.SelectorExpr); ok && isel.Sel.Name == "PathPrefix" {
+				if prefix, ok := x.routerPrefix(isel.X, routers); ok {
+					if p, ok := stringValue(inner.Args[0]); ok {
+						return prefix + p, true
+					}
+				}
+			}
+		case (sel.Sel.Name == "With" || sel.Sel.Name == "Use") && x.framework == "chi":
+			//router.With(middleware).Get(...)
+			return x.routerPrefix(sel.X, routers)
+		}
+	}
+	return "", false
+}
+
+//call records a route registration or follows a function the router is passed to
+func (x *extractor) call(call *ast.CallExpr, env *scope, routers map[string]string, consumed map[ast.Node]bool) {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		//gorilla: router.HandleFunc("/orders", handler.List).Methods(http.MethodGet)
+		if sel.Sel.Name == "Methods" && x.framework == "gorilla" {
+			if inner, ok := sel.X.(*ast.CallExpr); ok {
+				if isel, ok := inner.Fun.(*ast.SelectorExpr); ok && (isel.Sel.Name == "HandleFunc" || isel.Sel.Name == "Handle") && len(inner.Args) == 2 {
+					if prefix, ok := x.routerPrefix(isel.X, routers); ok {
+						for _, arg := range call.Args {
+							if method, ok := stringValue(arg); ok {
+								x.add(method, prefix, inner.Args[0], inner.Args[1], env)
+							}
+						}
+						consumed[inner] = true
+						return
+					}
+				}
+			}
+		}
+		if prefix, ok := x.routerPrefix(sel.X, routers); ok {
+			x.routeCall(sel.Sel.Name, call, prefix, env, routers, consumed)
+			return
+		}
+	}
+
+	fn := x.callee(call.Fun, env)
+	if fn == nil || fn.body == nil {
+		return
+	}
+	params := fn.params()
+	calleeRouters := map[string]string{}
+	for i, arg := range call.Args {
+		if i >= len(params) || params[i] == "" || params[i] == "_" {
+			continue
+		}
+		if prefix, ok := x.routerPrefix(arg, routers); ok {
+			calleeRouters[params[i]] = prefix
+		}
+	}
+	if len(calleeRouters) == 0 {
+		return
+	}
+	key := fmt.Sprintf("%s.%s.%s %v", fn.file.pkg.path, fn.recv, fn.name, calleeRouters)
+	if x.visited[key] {
+		return
+	}
+	x.visited[key] = true
+	x.walk(fn.body, funcScope(fn), calleeRouters)
+}
+
+//routeCall handles a method called on a router
+func (x *extractor) routeCall(name string, call *ast.CallExpr, prefix string, env *scope, routers map[string]string, consumed map[ast.Node]bool) {
+	args := call.Args
+	switch x.framework {
+	case "gin", "echo":
+		if name == strings.ToUpper(name) && isMethod(name) && len(args) >= 2 {
+			handler := args[1]
+			if x.framework == "gin" {
+				//gin takes the middleware before the handler
+				handler = args[len(args)-1]
+			}
+			x.add(name, prefix, args[0], handler, env)
+			return
+		}
+		if (name == "Handle" && x.framework == "gin" || name == "Add" && x.framework == "echo") && len(args) >= 3 {
+			if method, ok := stringValue(args[0]); ok {
+				handler := args[2]
+				if x.framework == "gin" {
+					handler = args[len(args)-1]
+				}
+				x.add(method, prefix, args[1], handler, env)
+			}
+		}
+	case "chi":
+		method := strings.ToUpper(name)
+		switch {
+		case name != method && isMethod(method) && len(args) == 2:
+			x.add(method, prefix, args[0], args[1], env)
+		case (name == "Method" || name == "MethodFunc") && len(args) == 3:
+			if method, ok := stringValue(args[0]); ok {
+				x.add(method, prefix, args[1], args[2], env)
+			}
+		case name == "Route" && len(args) == 2:
+			//router.Route("/v1", func(r chi.Router) {...})
+			if p, ok := stringValue(args[0]); ok {
+				x.subrouter(args[1], prefix+p, env, routers, consumed)
+			}
+		case name == "Group" && len(args) == 1:
+			x.subrouter(args[0], prefix, env, routers, consumed)
+		}
+	case "none":
+		if (name != "HandleFunc" && name != "Handle") || len(args) != 2 {
+			return
+		}
+		pattern, ok := stringValue(args[0])
+		if !ok {
+			return
+		}
+		//go 1.22 patterns: router.HandleFunc("GET /orders/{id}", handler.Get)
+		if i := strings.Index(pattern, " "); i > 0 && isMethod(pattern[:i]) {
+			x.addPath(pattern[:i], prefix+strings.TrimSpace(pattern[i+1:]), args[1], env)
+			return
+		}
+		//a subtree pattern serves the paths below it, the handlers read the id from the rest of the path
+		if strings.HasSuffix(pattern, "/") && pattern != "/" {
+			pattern += "{id}"
+		}
+		for _, c := range methodSwitch(args[1]) {
+			x.addPath(c.method, prefix+pattern, c.handler, env)
+		}
+	}
+}
+
+//methodCase a case of a switch on r.Method and the handler it calls
+type methodCase struct {
+	method  string
+	handler ast.Expr
+}
+
+//methodSwitch the cases of a net/http handler literal dispatching on the method,
+//switch r.Method { case http.MethodGet: handler.List(w, r) }
+func methodSwitch(expr ast.Expr) []methodCase {
+	lit, ok := expr.(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+	var cases []methodCase
+	for _, stmt := range lit.Body.List {
+		sw, ok := stmt.(*ast.SwitchStmt)
+		if !ok {
+			continue
+		}
+		if tag, ok := sw.Tag.(*ast.SelectorExpr); !ok || tag.Sel.Name != "Method" {
+			continue
+		}
+		for _, x := range sw.Body.List {
+			clause := x.(*ast.CaseClause)
+			if len(clause.Body) == 0 {
+				continue
+			}
+			exprStmt, ok := clause.Body[0].(*ast.ExprStmt)
+			if !ok {
+				continue
+			}
+			call, ok := exprStmt.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			for _, y := range clause.List {
+				if method, ok := stringValue(y); ok {
+					cases = append(cases, methodCase{method: method, handler: call.Fun})
+				}
+			}
+		}
+	}
+	return cases
+}
+
+//openAPIRoute records a route of the table gen:openapi generates for net/http,
+//openAPIRoute{method: "GET", path: "/orders/{id}", serve: func(...) {...}}
+func (x *extractor) openAPIRoute(lit *ast.CompositeLit, env *scope) {
+	if x.framework != "none" {
+		return
+	}
+	//the elements of []openAPIRoute{...} leave out the type
+	if array, ok := lit.Type.(*ast.ArrayType); ok {
+		if id, ok := array.Elt.(*ast.Ident); ok && id.Name == "openAPIRoute" {
+			for _, elt := range lit.Elts {
+				if route, ok := elt.(*ast.CompositeLit); ok && route.Type == nil {
+					x.openAPIRouteFields(route, env)
+				}
+			}
+		}
+		return
+	}
+	if id, ok := lit.Type.(*ast.Ident); ok && id.Name == "openAPIRoute" {
+		x.openAPIRouteFields(lit, env)
+	}
+}
+
+func (x *extractor) openAPIRouteFields(lit *ast.CompositeLit, env *scope) {
+	var method, p string
+	var serve ast.Expr
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, _ := kv.Key.(*ast.Ident)
+		switch {
+		case key == nil:
+		case key.Name == "method":
+			method, _ = stringValue(kv.Value)
+		case key.Name == "path":
+			p, _ = stringValue(kv.Value)
+		case key.Name == "serve":
+			serve = kv.Value
+		}
+	}
+	if method != "" && p != "" && serve != nil {
+		x.addPath(method, p, serve, env)
+	}
+}
+
+//subrouter walks a function literal that gets a router as its first parameter
+func (x *extractor) subrouter(expr ast.Expr, prefix string, env *scope, routers map[string]string, consumed map[ast.Node]bool) {
+	lit, ok := expr.(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	consumed[lit] = true
+	params := (&sourceFunc{typ: lit.Type}).params()
+	if len(params) == 0 || params[0] == "" {
+		return
+	}
+	inner := map[string]string{}
+	for k, v := range routers {
+		inner[k] = v
+	}
+	inner[params[0]] = prefix
+	scope := newScope(env.file, env)
+	scope.declare(lit.Type.Params)
+	x.walk(lit.Body, scope, inner)
+}
+
+func isMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+var (
+	//colonParam a gin or echo :name or *name path parameter
+	colonParam = regexp.MustCompile(`(^|/)[:*]([^/]+)`)
+	//patternParam a gorilla or chi {name:pattern} path parameter
+	patternParam = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+	//servePattern a net/http {name...} wildcard
+	servePattern = regexp.MustCompile(`\{([^}$.]+)\.\.\.\}`)
+)
+
+func (x *extractor) add(method, prefix string, pathExpr, handler ast.Expr, env *scope) {
+	if p, ok := stringValue(pathExpr); ok {
+		x.addPath(method, prefix+p, handler, env)
+	}
+}
+
+//addPath records a route, p is the full path in the syntax of the framework
+func (x *extractor) addPath(method, p string, handler ast.Expr, env *scope) {
+	method = strings.ToUpper(method)
+	if !isMethod(method) {
+		return
+	}
+	switch x.framework {
+	case "gin", "echo":
+		p = colonParam.ReplaceAllString(p, "$1{$2}")
+	case "none":
+		p = strings.TrimSuffix(servePattern.ReplaceAllString(p, "{$1}"), "{$}")
+	default:
+		p = patternParam.ReplaceAllString(p, "{$1}")
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	x.routes = append(x.routes, &route{method: method, path: strings.ReplaceAll(p, "//", "/"), handler: handler, env: env})
+}
+
+//stringValue a string literal, a concatenation of them or a net/http method constant
+func stringValue(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		v, err := strconv.Unquote(e.Value)
+		return v, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringValue(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringValue(e.Y)
+		return left + right, ok
+	case *ast.SelectorExpr:
+		if id, ok := e.X.(*ast.Ident); ok && id.Name == "http" && strings.HasPrefix(e.Sel.Name, "Method") {
+			return strings.ToUpper(strings.TrimPrefix(e.Sel.Name, "Method")), true
+		}
+	}
+	return "", false
+}
+
+//handler what a handler reads and writes
+type handler struct {
+	name string
+	//delegate method of a project interface the handler calls, the operations generated by gen:openapi are named after it
+	delegate  string
+	request   *typeRef
+	statuses  []int
+	responses map[int]*typeRef
+	params    []*Parameter
+	//paramTypes schema types of parameters converted from strings, by name
+	paramTypes map[string]string
+	visited    map[*ast.BlockStmt]bool
+}
+
+func (h *handler) param(in, name string) {
+	for _, x := range h.params {
+		if x.In == in && x.Name == name {
+			return
+		}
+	}
+	h.params = append(h.params, &Parameter{Name: name, In: in})
+}
+
+func (h *handler) respond(status int, body *typeRef) {
+	if _, ok := h.responses[status]; ok {
+		return
+	}
+	h.statuses = append(h.statuses, status)
+	h.responses[status] = body
+}
+
+//operation describes the route from its handler
+func (x *extractor) operation(r *route) *Operation {
+	h := &handler{responses: map[int]*typeRef{}, paramTypes: map[string]string{}, visited: map[*ast.BlockStmt]bool{}}
+	x.handler(h, r.handler, r.env)
+
+	op := &Operation{OperationID: x.operationID(r, h), Responses: map[string]*Response{}}
+	for _, m := range pathParam.FindAllStringSubmatch(r.path, -1) {
+		op.Parameters = append(op.Parameters, &Parameter{Name: m[1], In: "path", Required: true, Schema: paramSchema(h.paramTypes[m[1]])})
+	}
+	for _, p := range h.params {
+		p.Schema = paramSchema(h.paramTypes[p.Name])
+		op.Parameters = append(op.Parameters, p)
+	}
+	if h.request != nil {
+		if schema := x.schemas.schema(h.request); schema != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: jsonMedia(schema)}
+		}
+	}
+	for _, status := range h.statuses {
+		response := &Response{Description: http.StatusText(status)}
+		if schema := x.schemas.schema(h.responses[status]); schema != nil && !isError(h.responses[status]) {
+			response.Content = jsonMedia(schema)
+		}
+		op.Responses[strconv.Itoa(status)] = response
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	return op
+}
+
+func jsonMedia(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+//paramSchema schema of a parameter of the type, arrays are read as lists of strings
+func paramSchema(typ string) *Schema {
+	switch typ {
+	case "":
+		typ = "string"
+	case "array":
+		return &Schema{Type: typ, Items: &Schema{Type: "string"}}
+	}
+	return &Schema{Type: typ}
+}
+
+func isError(t *typeRef) bool {
+	if t == nil {
+		return false
+	}
+	id, ok := t.expr.(*ast.Ident)
+	return ok && id.Name == "error"
+}
+
+//operationID handler method and type, eg. listOrder for OrderHandler.List, or method and path for function literals
+func (x *extractor) operationID(r *route, h *handler) string {
+	name := h.delegate
+	if name == "" {
+		name = h.name
+	}
+	if name == "" {
+		name = strings.ToLower(r.method) + " " + strings.NewReplacer("/", " ", "{", " ", "}", " ").Replace(r.path)
+	}
+	id := templates.Camel(name)
+	unique := id
+	for i := 2; x.operationIDs[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+	x.operationIDs[unique] = true
+	return unique
+}
+
+//handler resolves the handler expression of a route and analyzes its body
+func (x *extractor) handler(h *handler, expr ast.Expr, env *scope) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		x.handler(h, e.X, env)
+	case *ast.CallExpr:
+		//conversions and adapters, eg. http.HandlerFunc(h.List)
+		if len(e.Args) == 1 {
+			x.handler(h, e.Args[0], env)
+		}
+	case *ast.FuncLit:
+		scope := newScope(env.file, env)
+		scope.declare(e.Type.Params)
+		//closures generated by gen:openapi only pass the request on to a serve function
+		if fn := x.delegated(e.Body, scope); fn != nil {
+			h.name = fn.name
+			x.analyze(h, fn.body, funcScope(fn), "")
+			return
+		}
+		x.analyze(h, e.Body, scope, "")
+	case *ast.Ident, *ast.SelectorExpr:
+		fn := x.callee(e, env)
+		if fn == nil || fn.body == nil {
+			return
+		}
+		h.name = fn.name + " " + strings.TrimSuffix(fn.recv, "Handler")
+		recv := ""
+		if fn.decl.Recv != nil && len(fn.decl.Recv.List[0].Names) > 0 {
+			recv = fn.decl.Recv.List[0].Names[0].Name
+		}
+		x.analyze(h, fn.body, funcScope(fn), recv)
+	}
+}
+
+//delegated the project function a short function literal calls, nil if it does more than that
+func (x *extractor) delegated(body *ast.BlockStmt, env *scope) *sourceFunc {
+	if len(body.List) == 0 || len(body.List) > 2 {
+		return nil
+	}
+	var call *ast.CallExpr
+	switch stmt := body.List[0].(type) {
+	case *ast.ExprStmt:
+		call, _ = stmt.X.(*ast.CallExpr)
+	case *ast.ReturnStmt:
+		if len(stmt.Results) == 1 {
+			call, _ = stmt.Results[0].(*ast.CallExpr)
+		}
+	}
+	if call == nil {
+		return nil
+	}
+	if fn := x.callee(call.Fun, env); fn != nil && fn.body != nil && fn.recv == "" {
+		return fn
+	}
+	return nil
+}
+
+var (
+	//bindCalls calls that decode the request body into their pointer argument
+	bindCalls = regexp.MustCompile(`^(ShouldBind|ShouldBindJSON|Bind|BindJSON|Decode|decode\w*|bind\w*)$`)
+	//respondCalls calls that take a status and optionally the response body after it
+	respondCalls = regexp.MustCompile(`(?i)^(json|indentedjson|purejson|securejson|jsonpretty|status|nocontent|writeheader|abortwithstatus|abortwithstatusjson|respond\w*|write\w*|render\w*)$`)
+	//queryCalls calls that read a query parameter named by their first argument
+	queryCalls = map[string]bool{"Query": true, "DefaultQuery": true, "GetQuery": true, "QueryParam": true, "QueryArray": true}
+	//pathCalls calls that read a path parameter named by their first argument
+	pathCalls = map[string]bool{"Param": true, "pathParam": true}
+	//conversions of parameter strings and the schema type they produce
+	conversions = []struct {
+		pattern *regexp.Regexp
+		typ     string
+	}{
+		{regexp.MustCompile(`(?i)^(atoi|parse(int|uint)\w*)$`), "integer"},
+		{regexp.MustCompile(`(?i)^parsefloat\w*$`), "number"},
+		{regexp.MustCompile(`(?i)^parsebool$`), "boolean"},
+	}
+)
+
+//analyze records the request body, responses and parameters of a handler body. Calls to other methods of the
+//receiver are followed, they usually write the error responses
+func (x *extractor) analyze(h *handler, body *ast.BlockStmt, env *scope, recv string) {
+	if body == nil || h.visited[body] {
+		return
+	}
+	h.visited[body] = true
+	x.bind(env, body)
+	converted := map[ast.Expr]string{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name := callName(call)
+		for _, c := range conversions {
+			if c.pattern.MatchString(name) {
+				for _, arg := range call.Args {
+					converted[arg] = c.typ
+				}
+			}
+		}
+		return true
+	})
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IndexExpr:
+			call, ok := node.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			name, ok := stringValue(node.Index)
+			switch {
+			case !ok:
+			case callName(call) == "Vars":
+				//gorilla: mux.Vars(r)["id"]
+				x.paramType(h, name, converted[node])
+			case callName(call) == "Query" || callName(call) == "QueryParams":
+				//every value of a repeated query parameter: r.URL.Query()["status"], echo c.QueryParams()["status"]
+				h.param("query", name)
+				x.paramType(h, name, "array")
+			}
+			return true
+		case *ast.CallExpr:
+			x.analyzeCall(h, node, env, recv, converted)
+		}
+		return true
+	})
+}
+
+func (x *extractor) analyzeCall(h *handler, call *ast.CallExpr, env *scope, recv string, converted map[ast.Expr]string) {
+	name := callName(call)
+	sel, isSelector := call.Fun.(*ast.SelectorExpr)
+	switch {
+	case bindCalls.MatchString(name):
+		for _, arg := range call.Args {
+			if u, ok := arg.(*ast.UnaryExpr); ok && u.Op == token.AND && h.request == nil {
+				h.request = x.typeOf(u.X, env)
+			}
+		}
+	case name == "NewHTTPError" && len(call.Args) > 0:
+		//echo: echo.NewHTTPError(http.StatusBadRequest, message) answers with {"message": message}
+		if status, ok := statusCode(call.Args[0]); ok {
+			h.respond(status, &typeRef{&ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("string")}, env.file})
+		}
+	case respondCalls.MatchString(name):
+		for i, arg := range call.Args {
+			status, ok := statusCode(arg)
+			if !ok {
+				continue
+			}
+			var body *typeRef
+			if i+1 < len(call.Args) {
+				body = x.typeOf(call.Args[i+1], env)
+				if body == nil {
+					body = &typeRef{&ast.InterfaceType{Methods: &ast.FieldList{}}, env.file}
+				}
+			}
+			h.respond(status, body)
+			break
+		}
+	case queryCalls[name] && len(call.Args) > 0:
+		if p, ok := stringValue(call.Args[0]); ok {
+			h.param("query", p)
+			x.paramType(h, p, converted[call])
+			if name == "QueryArray" {
+				x.paramType(h, p, "array")
+			}
+		}
+	case pathCalls[name] && len(call.Args) > 0:
+		if p, ok := stringValue(call.Args[0]); ok {
+			x.paramType(h, p, converted[call])
+		}
+	case name == "URLParam" && len(call.Args) == 2:
+		//chi: chi.URLParam(r, "id")
+		if p, ok := stringValue(call.Args[1]); ok {
+			x.paramType(h, p, converted[call])
+		}
+	case name == "GetHeader" && len(call.Args) == 1:
+		if p, ok := stringValue(call.Args[0]); ok {
+			h.param("header", p)
+		}
+	case name == "Get" && isSelector && len(call.Args) == 1:
+		p, ok := stringValue(call.Args[0])
+		if !ok {
+			break
+		}
+		//r.URL.Query().Get("limit") and r.Header.Get("X-Request-Id")
+		if inner, ok := sel.X.(*ast.CallExpr); ok && callName(inner) == "Query" {
+			h.param("query", p)
+			x.paramType(h, p, converted[call])
+		} else if field, ok := sel.X.(*ast.SelectorExpr); ok && field.Sel.Name == "Header" {
+			h.param("header", p)
+		}
+	case name == "Values" && isSelector && len(call.Args) == 1:
+		//every value of a repeated header: r.Header.Values("X-Tags")
+		if field, ok := sel.X.(*ast.SelectorExpr); ok && field.Sel.Name == "Header" {
+			if p, ok := stringValue(call.Args[0]); ok {
+				h.param("header", p)
+				x.paramType(h, p, "array")
+			}
+		}
+	}
+
+	if !isSelector {
+		return
+	}
+	if id, ok := sel.X.(*ast.Ident); ok {
+		if recv != "" && id.Name == recv {
+			if fn := x.callee(call.Fun, env); fn != nil && fn.body != nil {
+				x.analyze(h, fn.body, funcScope(fn), recv)
+			}
+			return
+		}
+		if h.delegate == "" && !env.isPackage(id.Name) && x.isInterface(env.lookup(id.Name)) {
+			h.delegate = sel.Sel.Name
+		}
+	}
+}
+
+//paramType remembers the type a parameter is converted to
+func (x *extractor) paramType(h *handler, name, typ string) {
+	if typ != "" {
+		h.paramTypes[name] = typ
+	}
+}
+
+//callName name of the function or method a call expression calls
+func callName(call *ast.CallExpr) string {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+//statusCode a http status literal or net/http status constant
+func statusCode(expr ast.Expr) (int, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT {
+			return 0, false
+		}
+		code, err := strconv.Atoi(e.Value)
+		return code, err == nil && code >= 100 && code < 600
+	case *ast.SelectorExpr:
+		if id, ok := e.X.(*ast.Ident); ok && id.Name == "http" {
+			code, ok := statusCodes[e.Sel.Name]
+			return code, ok
+		}
+	}
+	return 0, false
+}
+
+//statusCodes net/http status constants
+var statusCodes = map[string]int{}
+
+func init() {
+	for code := 100; code < 600; code++ {
+		text := http.StatusText(code)
+		if text == "" {
+			continue
+		}
+		statusCodes["Status"+strings.NewReplacer(" ", "", "-", "", "'", "").Replace(text)] = code
+	}
+	//constants whose name does not follow the status text
+	statusCodes["StatusNonAuthoritativeInfo"] = http.StatusNonAuthoritativeInfo
+	statusCodes["StatusProxyAuthRequired"] = http.StatusProxyAuthRequired
+	statusCodes["StatusRequestEntityTooLarge"] = http.StatusRequestEntityTooLarge
+	statusCodes["StatusRequestedRangeNotSatisfiable"] = http.StatusRequestedRangeNotSatisfiable
+	statusCodes["StatusTeapot"] = http.StatusTeapot
+	statusCodes["StatusUnprocessableEntity"] = http.StatusUnprocessableEntity
+}
@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/AkronimBlack/stock/pkg/diff"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected documents in testdata/extract")
+
+//TestExtract extracts the fixture project of every framework in testdata/extract/{framework} and compares the
+//document with testdata/extract/{framework}.yaml. Every fixture registers the same order routes below /v1
+//(the net/http one without the prefix ServeMux has no groups for) and a /health route in httpRouter()
+func TestExtract(t *testing.T) {
+	for _, framework := range ExtractFrameworks() {
+		t.Run(framework, func(t *testing.T) {
+			project, err := wizard.LoadProject(filepath.Join("testdata", "extract", framework))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if project.Framework != framework {
+				t.Fatalf("fixture is detected as %s", project.Framework)
+			}
+			doc, err := Extract(project, Info{Title: "shop", Version: "1.0.0"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := doc.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", "extract", framework+".yaml")
+			if *update {
+				if err = ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("extracted document differs:\n%s", diff.Unified(golden, "extracted", want, got))
+			}
+		})
+	}
+}
+
+func TestExtractUnsupportedFramework(t *testing.T) {
+	_, err := Extract(&wizard.Project{Framework: "fiber", MainFile: "main.go"}, Info{})
+	if err == nil {
+		t.Fatal("want an error for an unsupported framework")
+	}
+}
@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"log"
+	"path"
+
+	"github.com/AkronimBlack/stock/pkg/goedit"
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+)
+
+//Objects files generated for api relative to the project root. Every file is regenerated on each run
+//except the handler, where only new methods and changed signatures are merged into the existing file
+func (a *API) Objects(framework *wizard.Framework) []*wizard.Object {
+	return []*wizard.Object{
+		{
+			Name:      "application/{{.Name | snake}}_api.go",
+			Type:      wizard.TypeFile,
+			Template:  templates.Loader("openapi/types.go"),
+			Generated: true,
+		},
+		{
+			Name:      "infrastructure/transport/http/openapi.go",
+			Type:      wizard.TypeFile,
+			Template:  templates.Loader("openapi/openapi.go"),
+			Generated: true,
+		},
+		{
+			Name:      "infrastructure/transport/http/{{.Name | snake}}_api.go",
+			Type:      wizard.TypeFile,
+			Template:  templates.Loader("openapi/api.go"),
+			Generated: true,
+		},
+		{
+			Name:      "infrastructure/transport/http/{{.Name | snake}}_routes.go",
+			Type:      wizard.TypeFile,
+			Template:  templates.Loader(framework.RoutesTemplate),
+			Generated: true,
+		},
+		{
+			Name: "infrastructure/transport/http/{{.Name | snake}}_handler.go",
+			Type: wizard.TypeFile,
+			Edit: a.mergeHandler,
+		},
+	}
+}
+
+//mergeHandler renders the handler stubs, an existing handler only gets the methods and signatures that changed
+func (a *API) mergeHandler(existing []byte) ([]byte, error) {
+	stubs, err := templates.Render("openapi/handlers.go", a)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return format.Source(stubs)
+	}
+	file, err := goedit.Parse(templates.Snake(a.Name)+"_handler.go", existing)
+	if err != nil {
+		return nil, err
+	}
+	if err = file.MergeFuncs(stubs); err != nil {
+		return nil, err
+	}
+	stale, err := file.CommentOutStale(stubs, "stock: not an operation of "+a.Spec+" anymore, move the code you need and delete it")
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range stale {
+		log.Printf("%s is not an operation of %s anymore, it was commented out", x, a.Spec)
+	}
+	return file.Bytes()
+}
+
+//handlerVar package level variable in main.go holding the handler
+func (a *API) handlerVar() string {
+	return templates.Camel(a.Name) + "Handler"
+}
+
+//RouterObject edits mainFile so the handler is built in buildDependencies() and its routes registered in httpRouter()
+func (a *API) RouterObject(mainFile string) *wizard.Object {
+	return &wizard.Object{
+		Name: mainFile,
+		Type: wizard.TypeFile,
+		Edit: a.register,
+	}
+}
+
+func (a *API) register(src []byte) ([]byte, error) {
+	file, err := goedit.Parse("main.go", src)
+	if err != nil {
+		return nil, err
+	}
+	if err = file.AddImport("httptransport", path.Join(a.Module, "infrastructure/transport/http")); err != nil {
+		return nil, err
+	}
+	if err = file.AddVar(a.handlerVar(), "*httptransport."+a.Name+"Handler"); err != nil {
+		return nil, err
+	}
+	err = file.AddStatement("buildDependencies", fmt.Sprintf("%s = httptransport.New%sHandler()", a.handlerVar(), a.Name))
+	if err == nil {
+		err = file.AddStatement("httpRouter", fmt.Sprintf("httptransport.Register%sRoutes(router, %s)", a.Name, a.handlerVar()))
+	}
+	if errors.Is(err, goedit.ErrNotFound) {
+		return nil, fmt.Errorf("%w, use --no-register and register the routes by hand", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file.Bytes()
+}
+
+//NewConfig generator config writing the api files into the project directory
+func NewConfig(project *wizard.Project, a *API, conflictPolicy string) *wizard.Config {
+	config := wizard.NewConfig(a.Name, project.ModulePath, project.Names.Maintainer, a)
+	config.OutputDir = project.Dir
+	config.ConflictPolicy = conflictPolicy
+	return config
+}
@@ -0,0 +1,233 @@
+package openapi
+
+import (
+	"go/ast"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+//schemaBuilder turns go types into schemas, project types become components/schemas
+type schemaBuilder struct {
+	source     *source
+	components Schemas
+	//names component name of every project type, by package path and type name
+	names map[string]string
+}
+
+func newSchemaBuilder(s *source) *schemaBuilder {
+	return &schemaBuilder{
+		source:     s,
+		components: Schemas{Items: map[string]*Schema{}},
+		names:      map[string]string{},
+	}
+}
+
+//basicSchemas schemas of the predeclared types
+var basicSchemas = map[string]Schema{
+	"string":  {Type: "string"},
+	"bool":    {Type: "boolean"},
+	"int":     {Type: "integer"},
+	"int8":    {Type: "integer"},
+	"int16":   {Type: "integer"},
+	"int32":   {Type: "integer", Format: "int32"},
+	"int64":   {Type: "integer", Format: "int64"},
+	"uint":    {Type: "integer"},
+	"uint8":   {Type: "integer"},
+	"uint16":  {Type: "integer"},
+	"uint32":  {Type: "integer", Format: "int32"},
+	"uint64":  {Type: "integer", Format: "int64"},
+	"byte":    {Type: "integer"},
+	"rune":    {Type: "integer", Format: "int32"},
+	"float32": {Type: "number", Format: "float"},
+	"float64": {Type: "number", Format: "double"},
+	"error":   {Type: "string"},
+	"any":     {},
+}
+
+//externalSchemas schemas of the standard library types that are encoded as something else than an object
+var externalSchemas = map[string]Schema{
+	"time.Time":                {Type: "string", Format: "date-time"},
+	"time.Duration":            {Type: "integer", Format: "int64"},
+	"encoding/json.RawMessage": {},
+	"encoding/json.Number":     {Type: "number"},
+}
+
+//schema of t, nil for types that cannot be encoded as json like functions and channels
+func (b *schemaBuilder) schema(t *typeRef) *Schema {
+	if t == nil {
+		return nil
+	}
+	switch x := t.expr.(type) {
+	case *ast.StarExpr:
+		schema := b.schema(&typeRef{x.X, t.file})
+		if schema != nil && schema.Ref == "" {
+			//nil is json null, a reference can not be marked nullable in OpenAPI 3.0
+			schema.Nullable = true
+		}
+		return schema
+	case *ast.ParenExpr:
+		return b.schema(&typeRef{x.X, t.file})
+	case *ast.ArrayType:
+		if id, ok := x.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		items := b.schema(&typeRef{x.Elt, t.file})
+		if items == nil {
+			items = &Schema{}
+		}
+		return &Schema{Type: "array", Items: items}
+	case *ast.MapType:
+		values := b.schema(&typeRef{x.Value, t.file})
+		if values == nil {
+			values = &Schema{}
+		}
+		return &Schema{Type: "object", AdditionalProperties: &AdditionalProperties{Allowed: true, Schema: values}}
+	case *ast.InterfaceType:
+		return &Schema{}
+	case *ast.StructType:
+		return b.object(x, t.file)
+	case *ast.Ident:
+		if named := b.source.named(t); named != nil {
+			return b.component(named)
+		}
+		if basic, ok := basicSchemas[x.Name]; ok {
+			return &basic
+		}
+		return &Schema{}
+	case *ast.SelectorExpr:
+		if named := b.source.named(t); named != nil {
+			return b.component(named)
+		}
+		if id, ok := x.X.(*ast.Ident); ok {
+			if external, ok := externalSchemas[t.file.importPath(id.Name)+"."+x.Sel.Name]; ok {
+				return &external
+			}
+		}
+		//types of other modules, eg. gin.H
+		return &Schema{Type: "object"}
+	case *ast.IndexExpr:
+		if named := b.source.named(t); named != nil {
+			return b.component(named)
+		}
+		return &Schema{}
+	}
+	return nil
+}
+
+//component a reference to the component of a project type, built the first time it is used
+func (b *schemaBuilder) component(named *namedType) *Schema {
+	key := named.pkg.path + "." + named.name
+	name, ok := b.names[key]
+	if !ok {
+		name = named.name
+		if _, taken := b.components.Items[name]; taken {
+			name = templates.Pascal(named.pkg.name) + named.name
+		}
+		b.names[key] = name
+		b.components.Keys = append(b.components.Keys, name)
+		//registered before it is built so recursive types end in a reference
+		b.components.Items[name] = &Schema{}
+		if schema := b.schema(named.def); schema != nil {
+			b.components.Items[name] = schema
+		}
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+//object schema of a struct as encoding/json sees it: exported fields, json tags and promoted fields of embedded structs
+func (b *schemaBuilder) object(st *ast.StructType, file *sourceFile) *Schema {
+	schema := &Schema{Type: "object", Properties: Schemas{Items: map[string]*Schema{}}}
+	for _, x := range st.Fields.List {
+		name, omitempty, skip := jsonTag(x)
+		if skip {
+			continue
+		}
+		fieldType := &typeRef{x.Type, file}
+		if len(x.Names) == 0 && name == "" {
+			if embedded := b.source.underlying(fieldType); embedded != nil {
+				if est, ok := embedded.expr.(*ast.StructType); ok {
+					b.merge(schema, b.object(est, embedded.file))
+					continue
+				}
+			}
+			if !ast.IsExported(baseName(x.Type)) {
+				continue
+			}
+			b.property(schema, baseName(x.Type), b.schema(fieldType), omitempty)
+			continue
+		}
+		names := x.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(baseName(x.Type))}
+		}
+		for _, n := range names {
+			if !n.IsExported() {
+				continue
+			}
+			property := name
+			if property == "" {
+				property = n.Name
+			}
+			b.property(schema, property, b.schema(fieldType), omitempty)
+		}
+	}
+	return schema
+}
+
+func (b *schemaBuilder) property(schema *Schema, name string, property *Schema, omitempty bool) {
+	if property == nil {
+		return
+	}
+	if _, ok := schema.Properties.Items[name]; !ok {
+		schema.Properties.Keys = append(schema.Properties.Keys, name)
+	}
+	schema.Properties.Items[name] = property
+	if !omitempty && !schema.isRequired(name) {
+		schema.Required = append(schema.Required, name)
+	}
+}
+
+//merge adds the promoted properties of an embedded struct, fields of the outer struct win
+func (b *schemaBuilder) merge(schema, embedded *Schema) {
+	for _, name := range embedded.Properties.Keys {
+		if _, ok := schema.Properties.Items[name]; ok {
+			continue
+		}
+		b.property(schema, name, embedded.Properties.Items[name], !embedded.isRequired(name))
+	}
+}
+
+//jsonTag name and options of the json struct tag of a field
+func jsonTag(field *ast.Field) (name string, omitempty, skip bool) {
+	if field.Tag == nil {
+		return "", false, false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false, false
+	}
+	value, ok := reflect.StructTag(tag).Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if value == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(value, ",")
+	for _, x := range parts[1:] {
+		if x == "omitempty" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty, false
+}
+
+//sorted the components in alphabetical order so the document does not change with the order routes are registered in
+func (b *schemaBuilder) sorted() Schemas {
+	sort.Strings(b.components.Keys)
+	return b.components
+}
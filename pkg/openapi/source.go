@@ -0,0 +1,561 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//source the packages of a project, parsed on demand. Only syntax is used, nothing is type checked
+//so the project does not need to build and its dependencies do not need to be downloaded
+type source struct {
+	dir      string
+	module   string
+	fset     *token.FileSet
+	packages map[string]*sourcePackage
+	//errs files that could not be parsed
+	errs []error
+}
+
+type sourcePackage struct {
+	path    string
+	name    string
+	funcs   map[string]*sourceFunc
+	methods map[string]map[string]*sourceFunc
+	types   map[string]*typeRef
+	vars    map[string]*typeRef
+}
+
+//sourceFile resolves the package names used in one file
+type sourceFile struct {
+	source  *source
+	pkg     *sourcePackage
+	imports []*ast.ImportSpec
+}
+
+//sourceFunc a function, method or interface method. body is nil for interface methods
+type sourceFunc struct {
+	name string
+	recv string
+	decl *ast.FuncDecl
+	typ  *ast.FuncType
+	body *ast.BlockStmt
+	file *sourceFile
+}
+
+//typeRef a type expression and the file its names are resolved in
+type typeRef struct {
+	expr ast.Expr
+	file *sourceFile
+}
+
+//namedType a type declared in a project package
+type namedType struct {
+	pkg  *sourcePackage
+	name string
+	def  *typeRef
+}
+
+//scope variables and their types inside a function, package variables are looked up last
+type scope struct {
+	vars   map[string]*typeRef
+	parent *scope
+	file   *sourceFile
+}
+
+func newSource(dir, module string) *source {
+	return &source{
+		dir:      dir,
+		module:   module,
+		fset:     token.NewFileSet(),
+		packages: map[string]*sourcePackage{},
+	}
+}
+
+//load parses the package with importPath, nil for packages outside the project
+func (s *source) load(importPath string) *sourcePackage {
+	if p, ok := s.packages[importPath]; ok {
+		return p
+	}
+	s.packages[importPath] = nil
+	if importPath != s.module && !strings.HasPrefix(importPath, s.module+"/") {
+		return nil
+	}
+	dir := filepath.Join(s.dir, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, s.module), "/")))
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	p := &sourcePackage{
+		path:    importPath,
+		funcs:   map[string]*sourceFunc{},
+		methods: map[string]map[string]*sourceFunc{},
+		types:   map[string]*typeRef{},
+		vars:    map[string]*typeRef{},
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(s.fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			s.errs = append(s.errs, err)
+			continue
+		}
+		p.name = f.Name.Name
+		p.add(f, &sourceFile{source: s, pkg: p, imports: f.Imports})
+	}
+	s.packages[importPath] = p
+	return p
+}
+
+func (p *sourcePackage) add(f *ast.File, file *sourceFile) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			fn := &sourceFunc{name: d.Name.Name, decl: d, typ: d.Type, body: d.Body, file: file}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				p.funcs[fn.name] = fn
+				continue
+			}
+			fn.recv = baseName(d.Recv.List[0].Type)
+			if p.methods[fn.recv] == nil {
+				p.methods[fn.recv] = map[string]*sourceFunc{}
+			}
+			p.methods[fn.recv][fn.name] = fn
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch x := spec.(type) {
+				case *ast.TypeSpec:
+					p.types[x.Name.Name] = &typeRef{x.Type, file}
+				case *ast.ValueSpec:
+					if x.Type == nil {
+						continue
+					}
+					for _, name := range x.Names {
+						p.vars[name.Name] = &typeRef{x.Type, file}
+					}
+				}
+			}
+		}
+	}
+}
+
+//baseName name of a receiver or embedded type without pointer, package and type parameters
+func baseName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.StarExpr:
+		return baseName(x.X)
+	case *ast.ParenExpr:
+		return baseName(x.X)
+	case *ast.IndexExpr:
+		return baseName(x.X)
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.Ident:
+		return x.Name
+	}
+	return ""
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+//importPath the package a file refers to by name, empty if it imports none under that name
+func (f *sourceFile) importPath(name string) string {
+	for _, spec := range f.imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return importPath
+			}
+			continue
+		}
+		if p := f.source.load(importPath); p != nil {
+			if p.name == name {
+				return importPath
+			}
+			continue
+		}
+		//outside the project the package name is guessed from the path, eg. github.com/go-chi/chi/v5 and gopkg.in/yaml.v2
+		base := path.Base(importPath)
+		if majorVersion.MatchString(base) {
+			base = path.Base(path.Dir(importPath))
+		}
+		if i := strings.Index(base, ".v"); i > 0 {
+			base = base[:i]
+		}
+		if strings.TrimPrefix(base, "go-") == name {
+			return importPath
+		}
+	}
+	return ""
+}
+
+func newScope(file *sourceFile, parent *scope) *scope {
+	return &scope{vars: map[string]*typeRef{}, parent: parent, file: file}
+}
+
+//funcScope a scope with the receiver and parameters of fn
+func funcScope(fn *sourceFunc) *scope {
+	env := newScope(fn.file, nil)
+	if fn.decl != nil {
+		env.declare(fn.decl.Recv)
+	}
+	env.declare(fn.typ.Params)
+	return env
+}
+
+func (e *scope) declare(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			e.vars[name.Name] = &typeRef{field.Type, e.file}
+		}
+	}
+}
+
+//local a variable declared in the function, nil for package variables and names that are not variables
+func (e *scope) local(name string) *typeRef {
+	for x := e; x != nil; x = x.parent {
+		if t, ok := x.vars[name]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+func (e *scope) lookup(name string) *typeRef {
+	if t := e.local(name); t != nil {
+		return t
+	}
+	return e.file.pkg.vars[name]
+}
+
+//isPackage the name refers to an imported package and is not shadowed by a variable
+func (e *scope) isPackage(name string) bool {
+	return e.lookup(name) == nil && e.file.importPath(name) != ""
+}
+
+//named the project type expr refers to, nil for builtin and external types
+func (s *source) named(t *typeRef) *namedType {
+	if t == nil {
+		return nil
+	}
+	switch x := t.expr.(type) {
+	case *ast.Ident:
+		if def, ok := t.file.pkg.types[x.Name]; ok {
+			return &namedType{t.file.pkg, x.Name, def}
+		}
+	case *ast.SelectorExpr:
+		id, ok := x.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if p := s.load(t.file.importPath(id.Name)); p != nil {
+			if def, ok := p.types[x.Sel.Name]; ok {
+				return &namedType{p, x.Sel.Name, def}
+			}
+		}
+	case *ast.IndexExpr:
+		return s.named(&typeRef{x.X, t.file})
+	}
+	return nil
+}
+
+//deref strips pointers and parentheses
+func deref(t *typeRef) *typeRef {
+	for t != nil {
+		switch x := t.expr.(type) {
+		case *ast.StarExpr:
+			t = &typeRef{x.X, t.file}
+		case *ast.ParenExpr:
+			t = &typeRef{x.X, t.file}
+		default:
+			return t
+		}
+	}
+	return nil
+}
+
+//underlying follows project types to their definition, eg. the struct of *domain.Order
+func (s *source) underlying(t *typeRef) *typeRef {
+	for i := 0; i < 10; i++ {
+		t = deref(t)
+		named := s.named(t)
+		if named == nil {
+			return t
+		}
+		t = named.def
+	}
+	return t
+}
+
+//field type of the field name of a struct, promoted fields included
+func (s *source) field(t *typeRef, name string) *typeRef {
+	return s.fieldDepth(t, name, 0)
+}
+
+func (s *source) fieldDepth(t *typeRef, name string, depth int) *typeRef {
+	u := s.underlying(t)
+	if u == nil || depth > 5 {
+		return nil
+	}
+	st, ok := u.expr.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	for _, x := range st.Fields.List {
+		for _, n := range x.Names {
+			if n.Name == name {
+				return &typeRef{x.Type, u.file}
+			}
+		}
+	}
+	for _, x := range st.Fields.List {
+		if len(x.Names) != 0 {
+			continue
+		}
+		if baseName(x.Type) == name {
+			return &typeRef{x.Type, u.file}
+		}
+		if f := s.fieldDepth(&typeRef{x.Type, u.file}, name, depth+1); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+//method the method name of a project type, interface methods have no body
+func (s *source) method(t *typeRef, name string) *sourceFunc {
+	t = deref(t)
+	if named := s.named(t); named != nil {
+		if fn := named.pkg.methods[named.name][name]; fn != nil {
+			return fn
+		}
+	}
+	u := s.underlying(t)
+	if u == nil {
+		return nil
+	}
+	if it, ok := u.expr.(*ast.InterfaceType); ok {
+		for _, x := range it.Methods.List {
+			ft, ok := x.Type.(*ast.FuncType)
+			if !ok {
+				continue
+			}
+			for _, n := range x.Names {
+				if n.Name == name {
+					return &sourceFunc{name: name, typ: ft, file: u.file}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//isInterface t is a project interface type
+func (s *source) isInterface(t *typeRef) bool {
+	if s.named(deref(t)) == nil {
+		return false
+	}
+	u := s.underlying(t)
+	if u == nil {
+		return false
+	}
+	_, ok := u.expr.(*ast.InterfaceType)
+	return ok
+}
+
+//result the i-th result type of fn
+func (fn *sourceFunc) result(i int) *typeRef {
+	if fn == nil || fn.typ.Results == nil {
+		return nil
+	}
+	for _, x := range fn.typ.Results.List {
+		n := len(x.Names)
+		if n == 0 {
+			n = 1
+		}
+		if i < n {
+			return &typeRef{x.Type, fn.file}
+		}
+		i -= n
+	}
+	return nil
+}
+
+//params names of the parameters of fn in order, empty for unnamed ones
+func (fn *sourceFunc) params() []string {
+	var names []string
+	for _, x := range fn.typ.Params.List {
+		if len(x.Names) == 0 {
+			names = append(names, "")
+		}
+		for _, n := range x.Names {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
+
+//callee the project function or method fun calls, nil if it cannot be resolved
+func (s *source) callee(fun ast.Expr, env *scope) *sourceFunc {
+	switch x := fun.(type) {
+	case *ast.ParenExpr:
+		return s.callee(x.X, env)
+	case *ast.Ident:
+		if env.lookup(x.Name) == nil {
+			return env.file.pkg.funcs[x.Name]
+		}
+	case *ast.SelectorExpr:
+		if id, ok := x.X.(*ast.Ident); ok && env.isPackage(id.Name) {
+			if p := s.load(env.file.importPath(id.Name)); p != nil {
+				return p.funcs[x.Sel.Name]
+			}
+			return nil
+		}
+		if t := s.typeOf(x.X, env); t != nil {
+			return s.method(t, x.Sel.Name)
+		}
+	}
+	return nil
+}
+
+//typeOf the static type of expr as far as it can be followed through project code
+func (s *source) typeOf(expr ast.Expr, env *scope) *typeRef {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return env.lookup(x.Name)
+	case *ast.ParenExpr:
+		return s.typeOf(x.X, env)
+	case *ast.StarExpr:
+		if t := s.typeOf(x.X, env); t != nil {
+			if star, ok := t.expr.(*ast.StarExpr); ok {
+				return &typeRef{star.X, t.file}
+			}
+		}
+	case *ast.UnaryExpr:
+		if x.Op != token.AND {
+			return nil
+		}
+		if t := s.typeOf(x.X, env); t != nil {
+			return &typeRef{&ast.StarExpr{X: t.expr}, t.file}
+		}
+	case *ast.CompositeLit:
+		if x.Type != nil {
+			return &typeRef{x.Type, env.file}
+		}
+	case *ast.SelectorExpr:
+		if id, ok := x.X.(*ast.Ident); ok && env.isPackage(id.Name) {
+			if p := s.load(env.file.importPath(id.Name)); p != nil {
+				return p.vars[x.Sel.Name]
+			}
+			return nil
+		}
+		return s.field(s.typeOf(x.X, env), x.Sel.Name)
+	case *ast.IndexExpr:
+		u := s.underlying(s.typeOf(x.X, env))
+		if u == nil {
+			return nil
+		}
+		switch c := u.expr.(type) {
+		case *ast.ArrayType:
+			return &typeRef{c.Elt, u.file}
+		case *ast.MapType:
+			return &typeRef{c.Value, u.file}
+		}
+	case *ast.CallExpr:
+		if id, ok := x.Fun.(*ast.Ident); ok && len(x.Args) > 0 && env.lookup(id.Name) == nil {
+			switch id.Name {
+			case "new":
+				return &typeRef{&ast.StarExpr{X: x.Args[0]}, env.file}
+			case "make":
+				return &typeRef{x.Args[0], env.file}
+			}
+		}
+		return s.callee(x.Fun, env).result(0)
+	}
+	return nil
+}
+
+//bind declares the variables of body in env. Blocks are not scoped, the first declaration of a name wins
+func (s *source) bind(env *scope, body ast.Node) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			env.declare(x.Type.Params)
+		case *ast.AssignStmt:
+			if x.Tok != token.DEFINE {
+				return true
+			}
+			if len(x.Rhs) == 1 && len(x.Lhs) > 1 {
+				if call, ok := x.Rhs[0].(*ast.CallExpr); ok {
+					fn := s.callee(call.Fun, env)
+					for i, lhs := range x.Lhs {
+						s.assign(env, lhs, fn.result(i))
+					}
+				}
+				return true
+			}
+			for i, lhs := range x.Lhs {
+				if i < len(x.Rhs) {
+					s.assign(env, lhs, s.typeOf(x.Rhs[i], env))
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range x.Names {
+				if x.Type != nil {
+					s.assign(env, name, &typeRef{x.Type, env.file})
+				} else if i < len(x.Values) {
+					s.assign(env, name, s.typeOf(x.Values[i], env))
+				}
+			}
+		case *ast.RangeStmt:
+			if x.Tok != token.DEFINE {
+				return true
+			}
+			u := s.underlying(s.typeOf(x.X, env))
+			if u == nil {
+				return true
+			}
+			switch c := u.expr.(type) {
+			case *ast.ArrayType:
+				s.assign(env, x.Value, &typeRef{c.Elt, u.file})
+			case *ast.MapType:
+				s.assign(env, x.Key, &typeRef{c.Key, u.file})
+				s.assign(env, x.Value, &typeRef{c.Value, u.file})
+			}
+		}
+		return true
+	})
+}
+
+func (s *source) assign(env *scope, lhs ast.Expr, t *typeRef) {
+	id, ok := lhs.(*ast.Ident)
+	if !ok || id.Name == "_" || t == nil {
+		return
+	}
+	if _, exists := env.vars[id.Name]; !exists {
+		env.vars[id.Name] = t
+	}
+}
+
+func (s *source) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("parsing project: %w", s.errs[0])
+}
@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//Document the part of an OpenAPI 3 document used to generate server code
+type Document struct {
+	OpenAPI    string     `yaml:"openapi"`
+	Info       Info       `yaml:"info,omitempty"`
+	Paths      Paths      `yaml:"paths,omitempty"`
+	Components Components `yaml:"components,omitempty"`
+}
+
+type Info struct {
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version,omitempty"`
+}
+
+//Paths path items in the order they are declared
+type Paths struct {
+	Keys  []string
+	Items map[string]*PathItem
+}
+
+func (p Paths) MarshalYAML() (interface{}, error) {
+	result := yaml.MapSlice{}
+	for _, x := range p.Keys {
+		result = append(result, yaml.MapItem{Key: x, Value: p.Items[x]})
+	}
+	return result, nil
+}
+
+func (p *Paths) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&p.Items); err != nil {
+		return err
+	}
+	var err error
+	p.Keys, err = keys(unmarshal)
+	return err
+}
+
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters,omitempty"`
+	Get        *Operation   `yaml:"get,omitempty"`
+	Put        *Operation   `yaml:"put,omitempty"`
+	Post       *Operation   `yaml:"post,omitempty"`
+	Delete     *Operation   `yaml:"delete,omitempty"`
+	Patch      *Operation   `yaml:"patch,omitempty"`
+	Head       *Operation   `yaml:"head,omitempty"`
+	Options    *Operation   `yaml:"options,omitempty"`
+}
+
+//operations every operation of the path item by http method, in a fixed order
+func (p *PathItem) operations() ([]string, []*Operation) {
+	methods := []string{"GET", "PUT", "POST", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+	all := []*Operation{p.Get, p.Put, p.Post, p.Delete, p.Patch, p.Head, p.Options}
+	var resultMethods []string
+	var result []*Operation
+	for i, x := range all {
+		if x != nil {
+			resultMethods = append(resultMethods, methods[i])
+			result = append(result, x)
+		}
+	}
+	return resultMethods, result
+}
+
+type Operation struct {
+	OperationID string               `yaml:"operationId,omitempty"`
+	Summary     string               `yaml:"summary,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses,omitempty"`
+}
+
+type Parameter struct {
+	Ref      string `yaml:"$ref,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+	In       string `yaml:"in,omitempty"`
+	Required bool   `yaml:"required,omitempty"`
+	//Explode false when the values of an array are comma separated instead of repeated, query parameters only
+	Explode *bool   `yaml:"explode,omitempty"`
+	Schema  *Schema `yaml:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Ref      string                `yaml:"$ref,omitempty"`
+	Required bool                  `yaml:"required,omitempty"`
+	Content  map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+type Response struct {
+	Ref         string                `yaml:"$ref,omitempty"`
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema,omitempty"`
+}
+
+type Components struct {
+	Schemas    Schemas               `yaml:"schemas,omitempty"`
+	Parameters map[string]*Parameter `yaml:"parameters,omitempty"`
+}
+
+//Schemas named schemas in the order they are declared
+type Schemas struct {
+	Keys  []string
+	Items map[string]*Schema
+}
+
+func (s Schemas) MarshalYAML() (interface{}, error) {
+	result := yaml.MapSlice{}
+	for _, x := range s.Keys {
+		result = append(result, yaml.MapItem{Key: x, Value: s.Items[x]})
+	}
+	return result, nil
+}
+
+func (s *Schemas) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&s.Items); err != nil {
+		return err
+	}
+	var err error
+	s.Keys, err = keys(unmarshal)
+	return err
+}
+
+type Schema struct {
+	Ref         string    `yaml:"$ref,omitempty"`
+	Type        string    `yaml:"type,omitempty"`
+	Format      string    `yaml:"format,omitempty"`
+	Description string    `yaml:"description,omitempty"`
+	Properties  Schemas   `yaml:"properties,omitempty"`
+	Required    []string  `yaml:"required,omitempty"`
+	Items       *Schema   `yaml:"items,omitempty"`
+	AllOf       []*Schema `yaml:"allOf,omitempty"`
+	Nullable    bool      `yaml:"nullable,omitempty"`
+	//AdditionalProperties the values of an object used as a map
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties,omitempty"`
+
+	//goType the name given to an inline object schema
+	goType string
+}
+
+//AdditionalProperties additionalProperties of an object schema, a boolean or the schema of the values
+type AdditionalProperties struct {
+	Allowed bool
+	//Schema of the values, nil when any value is allowed
+	Schema *Schema
+}
+
+func (a AdditionalProperties) MarshalYAML() (interface{}, error) {
+	if a.Schema != nil {
+		return a.Schema, nil
+	}
+	return a.Allowed, nil
+}
+
+func (a *AdditionalProperties) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return unmarshal(&a.Schema)
+}
+
+func (s *Schema) isRequired(property string) bool {
+	for _, x := range s.Required {
+		if x == property {
+			return true
+		}
+	}
+	return false
+}
+
+//keys of a mapping in document order
+func keys(unmarshal func(interface{}) error) ([]string, error) {
+	var ordered yaml.MapSlice
+	if err := unmarshal(&ordered); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(ordered))
+	for _, x := range ordered {
+		result = append(result, fmt.Sprint(x.Key))
+	}
+	return result, nil
+}
+
+//Load reads an OpenAPI 3 document in yaml or json
+func Load(path string) (*Document, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+//Parse parses an OpenAPI 3 document in yaml or json
+func Parse(b []byte) (*Document, error) {
+	doc := &Document{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("only OpenAPI 3 documents are supported, got openapi: %q", doc.OpenAPI)
+	}
+	if len(doc.Paths.Keys) == 0 {
+		return nil, fmt.Errorf("the document has no paths")
+	}
+	return doc, nil
+}
+
+//schemaRef name of the component a $ref points to
+func schemaRef(ref string) (string, error) {
+	const prefix = "#/components/schemas/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("unsupported $ref %s, only %s... is supported", ref, prefix)
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+//pathParam a {name} path parameter
+var pathParam = regexp.MustCompile(`\{([^}/]+)\}`)
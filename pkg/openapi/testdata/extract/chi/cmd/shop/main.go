@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	httptransport "example.com/acme/shop/infrastructure/transport/http"
+)
+
+var router *chi.Mux
+
+func httpRouter() *chi.Mux {
+	if router != nil {
+		return router
+	}
+	router = chi.NewRouter()
+	router.Route("/v1", func(r chi.Router) {
+		httptransport.RegisterOrderRoutes(r, httptransport.NewOrderHandler())
+	})
+	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return router
+}
@@ -0,0 +1,5 @@
+module example.com/acme/shop
+
+go 1.16
+
+require github.com/go-chi/chi/v5 v5.0.7
@@ -0,0 +1,77 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"example.com/acme/shop/domain"
+)
+
+//OrderHandler http handlers for orders
+type OrderHandler struct{}
+
+//NewOrderHandler constructor
+func NewOrderHandler() *OrderHandler {
+	return &OrderHandler{}
+}
+
+//RegisterOrderRoutes adds the order routes to router
+func RegisterOrderRoutes(router chi.Router, handler *OrderHandler) {
+	router.Get("/orders", handler.List)
+	router.Get("/orders/{id:[0-9]+}", handler.Get)
+	router.Post("/orders", handler.Create)
+	router.Delete("/orders/{id}", handler.Delete)
+}
+
+//List GET /orders
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	tags := r.URL.Query()["tag"]
+	orders := []domain.Order{}
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	w.Header().Set("X-Request-Id", strings.Join(tags, ",")+r.Header.Get("X-Request-Id"))
+	h.respond(w, http.StatusOK, orders)
+}
+
+//Get GET /orders/{id}
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+	order := domain.Order{ID: id}
+	h.respond(w, http.StatusOK, order)
+}
+
+//Create POST /orders
+func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var request domain.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	order := &domain.Order{Total: request.Total}
+	h.respond(w, http.StatusCreated, order)
+}
+
+//Delete DELETE /orders/{id}
+func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrderHandler) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
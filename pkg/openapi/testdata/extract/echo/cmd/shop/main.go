@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	httptransport "example.com/acme/shop/infrastructure/transport/http"
+)
+
+var router *echo.Echo
+
+func httpRouter() *echo.Echo {
+	if router != nil {
+		return router
+	}
+	router = echo.New()
+	v1 := router.Group("/v1")
+	httptransport.RegisterOrderRoutes(v1, httptransport.NewOrderHandler())
+	router.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	return router
+}
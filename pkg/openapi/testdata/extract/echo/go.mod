@@ -0,0 +1,5 @@
+module example.com/acme/shop
+
+go 1.16
+
+require github.com/labstack/echo/v4 v4.6.1
@@ -0,0 +1,67 @@
+package httptransport
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"example.com/acme/shop/domain"
+)
+
+//OrderHandler http handlers for orders
+type OrderHandler struct{}
+
+//NewOrderHandler constructor
+func NewOrderHandler() *OrderHandler {
+	return &OrderHandler{}
+}
+
+//RegisterOrderRoutes adds the order routes to router
+func RegisterOrderRoutes(router *echo.Group, handler *OrderHandler) {
+	router.GET("/orders", handler.List)
+	router.GET("/orders/:id", handler.Get)
+	router.POST("/orders", handler.Create)
+	router.DELETE("/orders/:id", handler.Delete)
+}
+
+//List GET /orders
+func (h *OrderHandler) List(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	tags := c.QueryParams()["tag"]
+	orders := []domain.Order{}
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	c.Response().Header().Set("X-Request-Id", strings.Join(tags, ",")+c.Request().Header.Get("X-Request-Id"))
+	return c.JSON(http.StatusOK, orders)
+}
+
+//Get GET /orders/{id}
+func (h *OrderHandler) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+	order := domain.Order{ID: id}
+	return c.JSON(http.StatusOK, order)
+}
+
+//Create POST /orders
+func (h *OrderHandler) Create(c echo.Context) error {
+	var request domain.CreateOrderRequest
+	if err := c.Bind(&request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	order := &domain.Order{Total: request.Total}
+	return c.JSON(http.StatusCreated, order)
+}
+
+//Delete DELETE /orders/{id}
+func (h *OrderHandler) Delete(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
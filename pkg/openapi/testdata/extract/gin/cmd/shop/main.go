@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+
+	httptransport "example.com/acme/shop/infrastructure/transport/http"
+)
+
+var router *gin.Engine
+
+func httpRouter() *gin.Engine {
+	if router != nil {
+		return router
+	}
+	router = gin.New()
+	v1 := router.Group("/v1")
+	httptransport.RegisterOrderRoutes(v1, httptransport.NewOrderHandler())
+	router.GET("/health", func(c *gin.Context) {
+		c.Status(200)
+	})
+	return router
+}
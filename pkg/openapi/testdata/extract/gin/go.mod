@@ -0,0 +1,5 @@
+module example.com/acme/shop
+
+go 1.16
+
+require github.com/gin-gonic/gin v1.7.7
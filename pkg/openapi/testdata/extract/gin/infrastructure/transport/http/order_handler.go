@@ -0,0 +1,70 @@
+package httptransport
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"example.com/acme/shop/domain"
+)
+
+//OrderHandler http handlers for orders
+type OrderHandler struct{}
+
+//NewOrderHandler constructor
+func NewOrderHandler() *OrderHandler {
+	return &OrderHandler{}
+}
+
+//RegisterOrderRoutes adds the order routes to router
+func RegisterOrderRoutes(router *gin.RouterGroup, handler *OrderHandler) {
+	router.GET("/orders", handler.List)
+	router.GET("/orders/:id", handler.Get)
+	router.POST("/orders", handler.Create)
+	router.DELETE("/orders/:id", handler.Delete)
+}
+
+//List GET /orders
+func (h *OrderHandler) List(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tags := c.QueryArray("tag")
+	orders := []domain.Order{}
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	c.Header("X-Request-Id", strings.Join(tags, ",")+c.GetHeader("X-Request-Id"))
+	c.JSON(http.StatusOK, orders)
+}
+
+//Get GET /orders/{id}
+func (h *OrderHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	order := domain.Order{ID: id}
+	c.JSON(http.StatusOK, order)
+}
+
+//Create POST /orders
+func (h *OrderHandler) Create(c *gin.Context) {
+	var request domain.CreateOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	order := &domain.Order{Total: request.Total}
+	c.JSON(http.StatusCreated, order)
+}
+
+//Delete DELETE /orders/{id}
+func (h *OrderHandler) Delete(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
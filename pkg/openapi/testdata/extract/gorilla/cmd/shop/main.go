@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	httptransport "example.com/acme/shop/infrastructure/transport/http"
+)
+
+var router *mux.Router
+
+func httpRouter() *mux.Router {
+	if router != nil {
+		return router
+	}
+	router = mux.NewRouter()
+	v1 := router.PathPrefix("/v1").Subrouter()
+	httptransport.RegisterOrderRoutes(v1, httptransport.NewOrderHandler())
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	return router
+}
@@ -0,0 +1,5 @@
+module example.com/acme/shop
+
+go 1.16
+
+require github.com/gorilla/mux v1.8.0
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"net/http"
+
+	httptransport "example.com/acme/shop/infrastructure/transport/http"
+)
+
+var router *http.ServeMux
+
+func httpRouter() *http.ServeMux {
+	if router != nil {
+		return router
+	}
+	router = http.NewServeMux()
+	httptransport.RegisterOrderRoutes(router, httptransport.NewOrderHandler())
+	router.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return router
+}
@@ -0,0 +1,16 @@
+package domain
+
+//Order a placed order
+type Order struct {
+	ID     int64             `json:"id"`
+	Total  float64           `json:"total"`
+	Note   *string           `json:"note,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	secret string
+}
+
+//CreateOrderRequest body of a new order
+type CreateOrderRequest struct {
+	Total float64 `json:"total"`
+	Note  string  `json:"note,omitempty"`
+}
@@ -0,0 +1,92 @@
+package httptransport
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"strings"
+
+	"example.com/acme/shop/domain"
+)
+
+//OrderHandler http handlers for orders
+type OrderHandler struct{}
+
+//NewOrderHandler constructor
+func NewOrderHandler() *OrderHandler {
+	return &OrderHandler{}
+}
+
+//RegisterOrderRoutes adds the order routes to router
+func RegisterOrderRoutes(router *http.ServeMux, handler *OrderHandler) {
+	router.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handler.List(w, r)
+		case http.MethodPost:
+			handler.Create(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	router.HandleFunc("/orders/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handler.Get(w, r)
+		case http.MethodDelete:
+			handler.Delete(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+//List GET /orders
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	tags := r.URL.Query()["tag"]
+	orders := []domain.Order{}
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	w.Header().Set("X-Request-Id", strings.Join(tags, ",")+r.Header.Get("X-Request-Id"))
+	h.respond(w, http.StatusOK, orders)
+}
+
+//Get GET /orders/{id}
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/orders/"), 10, 64)
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+		return
+	}
+	order := domain.Order{ID: id}
+	h.respond(w, http.StatusOK, order)
+}
+
+//Create POST /orders
+func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var request domain.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.respond(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	order := &domain.Order{Total: request.Total}
+	h.respond(w, http.StatusCreated, order)
+}
+
+//Delete DELETE /orders/{id}
+func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrderHandler) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
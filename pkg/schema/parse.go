@@ -0,0 +1,622 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//Table a table parsed from CREATE TABLE and the ALTER TABLE statements that add to it
+type Table struct {
+	Name    string    `json:"name"`
+	Columns []*Column `json:"columns"`
+	//PrimaryKey primary key column names in key order, empty when the table has none
+	PrimaryKey []string `json:"primary_key"`
+}
+
+//Column a single column definition
+type Column struct {
+	Name string `json:"name"`
+	//Type lower case type name without size or precision, eg. varchar, timestamp with time zone, integer[]
+	Type string `json:"type"`
+	//Size the arguments of the type, eg. 1 for tinyint(1)
+	Size     string `json:"size,omitempty"`
+	Unsigned bool   `json:"unsigned,omitempty"`
+	Nullable bool   `json:"nullable"`
+	//Generated the database assigns the value: auto increment, serial, identity and computed columns
+	Generated  bool `json:"generated,omitempty"`
+	primaryKey bool
+}
+
+//Column column with the name, nil if the table has none. Names are matched case insensitive
+func (t *Table) Column(name string) *Column {
+	for _, x := range t.Columns {
+		if strings.EqualFold(x.Name, name) {
+			return x
+		}
+	}
+	return nil
+}
+
+//Parse reads every CREATE TABLE statement of a MySQL or Postgres schema, eg. the output of mysqldump --no-data
+//or pg_dump --schema-only. Primary keys, serial defaults and identities added by ALTER TABLE are applied
+//to their table, every other statement is skipped
+func Parse(src []byte) ([]*Table, error) {
+	tokens, err := tokenize(string(src))
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{byName: map[string]*Table{}}
+	for _, statement := range split(tokens) {
+		p.tokens, p.pos = statement, 0
+		switch {
+		case p.keywords("CREATE"):
+			err = p.create()
+		case p.keywords("ALTER", "TABLE"):
+			err = p.alter()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p.tables, nil
+}
+
+type tokenKind int
+
+const (
+	identifier tokenKind = iota
+	quotedIdentifier
+	stringLiteral
+	symbol
+)
+
+type item struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+//is reports if the item is the unquoted keyword
+func (t item) is(keyword string) bool {
+	return t.kind == identifier && strings.EqualFold(t.value, keyword)
+}
+
+//tokenize splits src into identifiers, quoted identifiers, literals and symbols, comments are dropped
+func tokenize(src string) ([]item, error) {
+	var tokens []item
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' || c == '-' && strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			start := line
+			value, n, ok := quoted(src[i:], c)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated %c", start, c)
+			}
+			kind := quotedIdentifier
+			if c == '\'' {
+				kind = stringLiteral
+			}
+			tokens = append(tokens, item{kind: kind, value: value, line: start})
+			line += strings.Count(src[i:i+n], "\n")
+			i += n
+		case c == '$' && dollarTag(src[i:]) != "":
+			//postgres dollar quoted string, eg. a function body $$ ... $$
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated %s", line, tag)
+			}
+			tokens = append(tokens, item{kind: stringLiteral, value: src[i+len(tag) : i+len(tag)+end], line: line})
+			line += strings.Count(src[i:i+2*len(tag)+end], "\n")
+			i += 2*len(tag) + end
+		case c == '_' || c == '$' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || c >= 0x80:
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '$' || src[j] >= 0x80 ||
+				unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, item{kind: identifier, value: src[i:j], line: line})
+			i = j
+		default:
+			tokens = append(tokens, item{kind: symbol, value: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+//dollarTag the opening tag of a dollar quoted string at the start of src, $$ or $name$, empty if there is none
+func dollarTag(src string) string {
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '$':
+			return src[:i+1]
+		case c != '_' && !unicode.IsLetter(rune(c)) && (i == 1 || !unicode.IsDigit(rune(c))):
+			return ""
+		}
+	}
+	return ""
+}
+
+//quoted value of the quoted string at the start of src and its length. A doubled quote is a quote,
+//in strings a backslash escapes the next character
+func quoted(src string, quote byte) (string, int, bool) {
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch {
+		case src[i] == '\\' && quote == '\'' && i+1 < len(src):
+			i++
+			b.WriteByte(src[i])
+		case src[i] != quote:
+			b.WriteByte(src[i])
+		case i+1 < len(src) && src[i+1] == quote:
+			i++
+			b.WriteByte(quote)
+		default:
+			return b.String(), i + 1, true
+		}
+	}
+	return "", 0, false
+}
+
+//split tokens into statements at every ;
+func split(tokens []item) [][]item {
+	var statements [][]item
+	start := 0
+	for i, x := range tokens {
+		if x.kind == symbol && x.value == ";" {
+			if i > start {
+				statements = append(statements, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
+
+type parser struct {
+	tokens []item
+	pos    int
+	tables []*Table
+	byName map[string]*Table
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() item {
+	if p.done() {
+		return item{kind: symbol}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() item {
+	x := p.peek()
+	p.pos++
+	return x
+}
+
+//keywords consumes the keywords when the statement continues with all of them
+func (p *parser) keywords(keywords ...string) bool {
+	for i, x := range keywords {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(x) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *parser) symbol(value string) bool {
+	if x := p.peek(); x.kind == symbol && x.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+		if !p.done() {
+			line = p.peek().line
+		}
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+//name a possibly schema qualified name, only the last part is kept
+func (p *parser) name() (string, error) {
+	var name string
+	for {
+		x := p.next()
+		if x.kind != identifier && x.kind != quotedIdentifier {
+			return "", p.errorf("expected a name, got %q", x.value)
+		}
+		name = x.value
+		if !p.symbol(".") {
+			return name, nil
+		}
+	}
+}
+
+//skipGroup skips a parenthesized group, the opening parenthesis is already consumed
+func (p *parser) skipGroup() error {
+	for depth := 1; depth > 0; {
+		if p.done() {
+			return p.errorf("missing )")
+		}
+		x := p.next()
+		if x.kind == symbol && x.value == "(" {
+			depth++
+		}
+		if x.kind == symbol && x.value == ")" {
+			depth--
+		}
+	}
+	return nil
+}
+
+//element tokens of one table element up to the comma or parenthesis that ends it, which is consumed
+func (p *parser) element() ([]item, bool, error) {
+	start := p.pos
+	for depth := 0; ; {
+		if p.done() {
+			return nil, false, p.errorf("missing ) after the table elements")
+		}
+		x := p.next()
+		if x.kind != symbol {
+			continue
+		}
+		switch x.value {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				return p.tokens[start : p.pos-1], true, nil
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				return p.tokens[start : p.pos-1], false, nil
+			}
+		}
+	}
+}
+
+//create parses CREATE [TEMPORARY] TABLE [IF NOT EXISTS] name (elements), other CREATE statements are skipped
+func (p *parser) create() error {
+	p.keywords("OR", "REPLACE")
+	for _, x := range []string{"TEMPORARY", "TEMP", "UNLOGGED", "GLOBAL", "LOCAL"} {
+		p.keywords(x)
+	}
+	if !p.keywords("TABLE") {
+		return nil
+	}
+	p.keywords("IF", "NOT", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if !p.symbol("(") {
+		return p.errorf("table %s: only CREATE TABLE with column definitions is supported", name)
+	}
+	table := &Table{Name: name}
+	for last := false; !last; {
+		var element []item
+		if element, last, err = p.element(); err != nil {
+			return err
+		}
+		if len(element) == 0 {
+			continue
+		}
+		if err = table.add(element); err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+	if len(table.Columns) == 0 {
+		return fmt.Errorf("table %s has no columns", name)
+	}
+	if _, ok := p.byName[strings.ToLower(name)]; ok {
+		return fmt.Errorf("table %s is created twice", name)
+	}
+	p.byName[strings.ToLower(name)] = table
+	p.tables = append(p.tables, table)
+	return nil
+}
+
+//alter applies what pg_dump adds after CREATE TABLE: ADD CONSTRAINT x PRIMARY KEY (...),
+//ALTER COLUMN x SET DEFAULT nextval(...) and ALTER COLUMN x ADD GENERATED ... AS IDENTITY
+func (p *parser) alter() error {
+	p.keywords("IF", "EXISTS")
+	p.keywords("ONLY")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	table := p.byName[strings.ToLower(name)]
+	if table == nil {
+		return nil
+	}
+	for !p.done() {
+		element, _, err := p.alteration()
+		if err != nil {
+			return err
+		}
+		if err = table.alter(element); err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+//alteration tokens up to the next comma outside of parentheses, the statement has no closing parenthesis
+func (p *parser) alteration() ([]item, bool, error) {
+	start := p.pos
+	for depth := 0; !p.done(); {
+		x := p.next()
+		if x.kind != symbol {
+			continue
+		}
+		switch {
+		case x.value == "(":
+			depth++
+		case x.value == ")":
+			depth--
+		case x.value == "," && depth == 0:
+			return p.tokens[start : p.pos-1], false, nil
+		}
+	}
+	return p.tokens[start:], true, nil
+}
+
+//constraintKeywords keywords starting a table constraint instead of a column definition
+var constraintKeywords = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "KEY", "INDEX", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL", "EXCLUDE", "LIKE", "PERIOD"}
+
+//typeWords words that continue a type name, eg. double precision, timestamp with time zone, int unsigned
+var typeWords = map[string]bool{
+	"precision": true, "varying": true, "with": true, "without": true, "time": true, "zone": true,
+	"unsigned": true, "signed": true, "zerofill": true,
+}
+
+//add a column definition or table constraint
+func (t *Table) add(element []item) error {
+	for _, x := range constraintKeywords {
+		if element[0].is(x) {
+			return t.constraint(element)
+		}
+	}
+	p := &parser{tokens: element}
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if t.Column(name) != nil {
+		return fmt.Errorf("column %s is defined twice", name)
+	}
+	column := &Column{Name: name, Nullable: true}
+	var words []string
+	for !p.done() {
+		x := p.peek()
+		switch {
+		case x.kind == symbol && x.value == "(" && len(words) > 0:
+			p.next()
+			start := p.pos
+			if err = p.skipGroup(); err != nil {
+				return err
+			}
+			column.Size = join(p.tokens[start : p.pos-1])
+			continue
+		case x.kind == symbol && (x.value == "[" || x.value == "]") && len(words) > 0:
+			words[len(words)-1] += x.value
+			p.next()
+			continue
+		case x.kind == identifier && (len(words) == 0 || typeWords[strings.ToLower(x.value)]):
+			p.next()
+			switch strings.ToLower(x.value) {
+			case "unsigned":
+				column.Unsigned = true
+			case "signed", "zerofill":
+			default:
+				words = append(words, strings.ToLower(x.value))
+			}
+			continue
+		}
+		break
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("column %s has no type", name)
+	}
+	column.Type = strings.Join(words, " ")
+	if strings.HasSuffix(column.Type, "serial") {
+		column.Generated = true
+	}
+	column.constraints(p.tokens[p.pos:])
+	if column.primaryKey {
+		t.PrimaryKey = []string{column.Name}
+		column.Nullable = false
+	}
+	t.Columns = append(t.Columns, column)
+	return nil
+}
+
+//constraints applies the column constraints, only nullability, primary key and generated values matter
+func (c *Column) constraints(tokens []item) {
+	for i := 0; i < len(tokens); i++ {
+		x := tokens[i]
+		if x.kind == symbol && x.value == "(" {
+			//skip groups, CHECK (x IS NOT NULL) says nothing about the column
+			for depth := 1; depth > 0 && i+1 < len(tokens); {
+				i++
+				if tokens[i].kind == symbol && tokens[i].value == "(" {
+					depth++
+				}
+				if tokens[i].kind == symbol && tokens[i].value == ")" {
+					depth--
+				}
+			}
+			continue
+		}
+		prev := item{}
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		switch {
+		case x.is("NULL") && prev.is("NOT"):
+			c.Nullable = false
+		case x.is("KEY") && prev.is("PRIMARY"):
+			c.primaryKey = true
+		case x.is("AUTO_INCREMENT"), x.is("AUTOINCREMENT"), x.is("IDENTITY"), x.is("GENERATED"):
+			c.Generated = true
+		case x.is("AS") && i+1 < len(tokens) && tokens[i+1].value == "(":
+			c.Generated = true
+		case x.is("nextval") && prev.is("DEFAULT"):
+			c.Generated = true
+		}
+	}
+}
+
+//constraint applies a table constraint, only the primary key matters
+func (t *Table) constraint(element []item) error {
+	p := &parser{tokens: element}
+	if p.keywords("CONSTRAINT") {
+		if x := p.peek(); !x.is("PRIMARY") {
+			p.next()
+		}
+	}
+	if !p.keywords("PRIMARY", "KEY") {
+		return nil
+	}
+	//mysql allows an index type, PRIMARY KEY USING BTREE (id)
+	for !p.done() && !p.symbol("(") {
+		p.next()
+	}
+	var key []string
+	for !p.done() {
+		x := p.next()
+		switch {
+		case x.kind == symbol && x.value == ")":
+			return t.setPrimaryKey(key)
+		case x.kind == symbol && x.value == "(":
+			//prefix length of an index column, KEY (`name`(10))
+			if err := p.skipGroup(); err != nil {
+				return err
+			}
+		case x.kind == identifier && (x.is("ASC") || x.is("DESC")):
+		case x.kind == identifier || x.kind == quotedIdentifier:
+			key = append(key, x.value)
+		}
+	}
+	return fmt.Errorf("missing ) after the primary key columns")
+}
+
+//alter applies a single alteration of ALTER TABLE
+func (t *Table) alter(element []item) error {
+	p := &parser{tokens: element}
+	switch {
+	case p.keywords("ADD"):
+		if p.keywords("COLUMN") {
+			p.keywords("IF", "NOT", "EXISTS")
+			return t.add(p.tokens[p.pos:])
+		}
+		for _, x := range constraintKeywords {
+			if p.peek().is(x) {
+				return t.constraint(p.tokens[p.pos:])
+			}
+		}
+		return t.add(p.tokens[p.pos:])
+	case p.keywords("DROP"):
+		p.keywords("COLUMN")
+		p.keywords("IF", "EXISTS")
+		if x := p.peek(); x.kind == identifier || x.kind == quotedIdentifier {
+			t.drop(x.value)
+		}
+	case p.keywords("ALTER"):
+		p.keywords("COLUMN")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		column := t.Column(name)
+		if column == nil {
+			return fmt.Errorf("column %s does not exist", name)
+		}
+		switch {
+		case p.keywords("SET", "DEFAULT"):
+			column.Generated = column.Generated || p.peek().is("nextval")
+		case p.keywords("ADD", "GENERATED"):
+			column.Generated = true
+		case p.keywords("SET", "NOT", "NULL"):
+			column.Nullable = false
+		case p.keywords("DROP", "NOT", "NULL"):
+			column.Nullable = true
+		}
+	}
+	return nil
+}
+
+//drop removes the column, also from the primary key
+func (t *Table) drop(name string) {
+	for i, x := range t.Columns {
+		if strings.EqualFold(x.Name, name) {
+			t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+			break
+		}
+	}
+	for i, x := range t.PrimaryKey {
+		if strings.EqualFold(x, name) {
+			t.PrimaryKey = append(t.PrimaryKey[:i], t.PrimaryKey[i+1:]...)
+			break
+		}
+	}
+}
+
+func (t *Table) setPrimaryKey(key []string) error {
+	if len(key) == 0 {
+		return fmt.Errorf("primary key has no columns")
+	}
+	for i, name := range key {
+		column := t.Column(name)
+		if column == nil {
+			return fmt.Errorf("primary key column %s does not exist", name)
+		}
+		column.Nullable = false
+		key[i] = column.Name
+	}
+	t.PrimaryKey = key
+	return nil
+}
+
+//join tokens back into text, eg. the precision 10,2 of decimal(10,2)
+func join(tokens []item) string {
+	var b strings.Builder
+	for _, x := range tokens {
+		b.WriteString(x.value)
+	}
+	return b.String()
+}
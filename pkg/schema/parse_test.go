@@ -0,0 +1,211 @@
+package schema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []*Table
+	}{
+		{
+			fixture: "mysql.sql",
+			want: []*Table{
+				{
+					Name: "customers",
+					Columns: []*Column{
+						{Name: "id", Type: "bigint", Unsigned: true, Generated: true},
+						{Name: "email", Type: "varchar", Size: "255"},
+						{Name: "name", Type: "varchar", Size: "100", Nullable: true},
+						{Name: "active", Type: "tinyint", Size: "1"},
+						{Name: "balance", Type: "decimal", Size: "10,2"},
+						{Name: "created_at", Type: "timestamp"},
+						{Name: "updated_at", Type: "datetime", Nullable: true},
+						{Name: "full_name", Type: "varchar", Size: "357", Nullable: true, Generated: true},
+					},
+					PrimaryKey: []string{"id"},
+				},
+				{
+					Name: "order_items",
+					Columns: []*Column{
+						{Name: "order_id", Type: "bigint", Unsigned: true},
+						{Name: "product_id", Type: "int"},
+						{Name: "quantity", Type: "smallint", Unsigned: true},
+						{Name: "note", Type: "text", Nullable: true},
+					},
+					PrimaryKey: []string{"order_id", "product_id"},
+				},
+				{
+					Name: "audit log",
+					Columns: []*Column{
+						{Name: "event", Type: "varchar", Size: "50"},
+						{Name: "key", Type: "varchar", Size: "32", Nullable: true},
+						{Name: "payload", Type: "json", Nullable: true},
+						{Name: "logged`at", Type: "datetime", Size: "6"},
+					},
+				},
+			},
+		},
+		{
+			fixture: "postgres.sql",
+			want: []*Table{
+				{
+					Name: "customers",
+					Columns: []*Column{
+						{Name: "id", Type: "bigint", Generated: true},
+						{Name: "email", Type: "character varying", Size: "255"},
+						{Name: "Display Name", Type: "text", Nullable: true},
+						{Name: "tags", Type: "text[]"},
+						{Name: "balance", Type: "numeric", Size: "10,2"},
+						{Name: "created_at", Type: "timestamp with time zone"},
+						{Name: "deleted_at", Type: "timestamp without time zone", Nullable: true},
+					},
+					PrimaryKey: []string{"id"},
+				},
+				{
+					Name: "order_items",
+					Columns: []*Column{
+						{Name: "order_id", Type: "bigint"},
+						{Name: "product_id", Type: "integer"},
+						{Name: "quantity", Type: "integer"},
+					},
+					PrimaryKey: []string{"order_id", "product_id"},
+				},
+				{
+					Name: "orders",
+					Columns: []*Column{
+						{Name: "id", Type: "integer", Generated: true},
+						{Name: "customer_id", Type: "bigint", Nullable: true},
+						{Name: "status", Type: "character varying", Size: "20"},
+						{Name: "status_label", Type: "text", Nullable: true, Generated: true},
+					},
+					PrimaryKey: []string{"id"},
+				},
+				{
+					Name: "audit_log",
+					Columns: []*Column{
+						{Name: "event", Type: "text"},
+						{Name: "payload", Type: "jsonb", Nullable: true},
+					},
+				},
+			},
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.fixture, func(t *testing.T) {
+			src, err := ioutil.ReadFile(filepath.Join("testdata", x.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tables, err := Parse(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			//compared as json, the exported fields are what generators get to see
+			got, want := marshal(t, tables), marshal(t, x.want)
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []*Table
+	}{
+		{
+			name: "primary key added by alter table",
+			src:  "CREATE TABLE a (x int, y int); ALTER TABLE a ADD PRIMARY KEY (y, x)",
+			want: []*Table{{
+				Name:       "a",
+				Columns:    []*Column{{Name: "x", Type: "int"}, {Name: "y", Type: "int"}},
+				PrimaryKey: []string{"y", "x"},
+			}},
+		},
+		{
+			name: "primary key column names take the case of the column",
+			src:  `CREATE TABLE a ("Id" int, PRIMARY KEY (id))`,
+			want: []*Table{{Name: "a", Columns: []*Column{{Name: "Id", Type: "int"}}, PrimaryKey: []string{"Id"}}},
+		},
+		{
+			name: "dropped key column",
+			src:  "CREATE TABLE a (x int, y int, PRIMARY KEY (x, y)); ALTER TABLE a DROP COLUMN y",
+			want: []*Table{{Name: "a", Columns: []*Column{{Name: "x", Type: "int"}}, PrimaryKey: []string{"x"}}},
+		},
+		{
+			name: "sqlite autoincrement",
+			src:  "CREATE TABLE IF NOT EXISTS a (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)",
+			want: []*Table{{
+				Name:       "a",
+				Columns:    []*Column{{Name: "id", Type: "integer", Generated: true}, {Name: "name", Type: "text"}},
+				PrimaryKey: []string{"id"},
+			}},
+		},
+		{
+			name: "serial",
+			src:  "CREATE TEMP TABLE a (id bigserial PRIMARY KEY)",
+			want: []*Table{{Name: "a", Columns: []*Column{{Name: "id", Type: "bigserial", Generated: true}}, PrimaryKey: []string{"id"}}},
+		},
+		{
+			name: "alter table of an unknown table",
+			src:  "ALTER TABLE b ADD PRIMARY KEY (id); CREATE TABLE a (x int)",
+			want: []*Table{{Name: "a", Columns: []*Column{{Name: "x", Type: "int", Nullable: true}}}},
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			tables, err := Parse([]byte(x.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, want := marshal(t, tables), marshal(t, x.want)
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"duplicate column", "CREATE TABLE a (x int, X int)", "column X is defined twice"},
+		{"duplicate table", "CREATE TABLE a (x int); CREATE TABLE A (y int)", "table A is created twice"},
+		{"no type", "CREATE TABLE a (x)", "column x has no type"},
+		{"primary key of a missing column", "CREATE TABLE a (PRIMARY KEY (x))", "primary key column x does not exist"},
+		{"create table as", "CREATE TABLE a AS SELECT 1", "only CREATE TABLE with column definitions"},
+		{"missing parenthesis", "CREATE TABLE a (x int", "missing )"},
+		{"unterminated string", "CREATE TABLE a (x int DEFAULT 'a)", "line 1: unterminated '"},
+		{"unterminated quoted identifier", "CREATE TABLE a (\n`x int)", "line 2: unterminated `"},
+		{"unterminated dollar quote", "CREATE FUNCTION f() AS $$ SELECT 1;", "unterminated $$"},
+		{"unterminated comment", "/* CREATE TABLE a (x int)", "unterminated comment"},
+		{"unknown column altered", "CREATE TABLE a (x int); ALTER TABLE a ALTER COLUMN y SET NOT NULL", "column y does not exist"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			_, err := Parse([]byte(x.src))
+			if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, x.wantErr)
+			}
+		})
+	}
+}
+
+func marshal(t *testing.T, tables []*Table) string {
+	b, err := json.MarshalIndent(tables, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,379 @@
+package schema
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AkronimBlack/stock/common"
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"github.com/AkronimBlack/stock/pkg/wizard"
+)
+
+const (
+	postgresDialect = "postgres"
+	mysqlDialect    = "mysql"
+	sqliteDialect   = "sqlite"
+)
+
+//Dialects databases the queries can be written for
+func Dialects() []string {
+	return []string{postgresDialect, mysqlDialect, sqliteDialect}
+}
+
+func isDialect(name string) bool {
+	for _, x := range Dialects() {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
+
+//reservedVars names that would shadow a keyword or a package imported by the generated files
+var reservedVars = map[string]bool{
+	"context": true, "domain": true, "errors": true, "json": true, "repositories": true, "sql": true, "time": true,
+	"r": true, "err": true, "result": true, "rows": true,
+}
+
+//plainIdentifier identifiers that never need quoting unless they are reserved words
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+//reservedWords sql keywords that are commonly used as table or column names and have to be quoted
+var reservedWords = map[string]bool{
+	"all": true, "and": true, "as": true, "asc": true, "between": true, "by": true, "case": true, "check": true,
+	"column": true, "constraint": true, "create": true, "current_date": true, "current_time": true,
+	"current_timestamp": true, "current_user": true, "default": true, "delete": true, "desc": true,
+	"distinct": true, "drop": true, "else": true, "end": true, "exists": true, "from": true, "grant": true,
+	"group": true, "having": true, "in": true, "index": true, "insert": true, "into": true, "is": true,
+	"join": true, "key": true, "like": true, "limit": true, "not": true, "null": true, "offset": true, "on": true,
+	"or": true, "order": true, "primary": true, "range": true, "rank": true, "references": true, "rows": true,
+	"select": true, "set": true, "table": true, "then": true, "to": true, "union": true, "unique": true,
+	"update": true, "user": true, "using": true, "values": true, "when": true, "where": true, "with": true,
+}
+
+//Field a column of the table as a struct field
+type Field struct {
+	//Name go name of the field
+	Name   string `json:"name"`
+	Column string `json:"column"`
+	GoType string `json:"go_type"`
+	//Var parameter name when the field is part of the primary key
+	Var       string `json:"var,omitempty"`
+	Generated bool   `json:"generated,omitempty"`
+}
+
+//Tag go literal of the db and json struct tag. The column is quoted the way tag values are read back,
+//a column with a backtick makes it an interpreted string literal, eg. "db:\"logged`at\" json:\"logged`at\""
+func (f *Field) Tag() string {
+	column := strconv.Quote(f.Column)
+	return literal("db:" + column + " json:" + column)
+}
+
+//Model template data for the files generated for a table
+type Model struct {
+	//Name go name of a row, the singular of the table name (order_items => OrderItem)
+	Name    string   `json:"name"`
+	Table   string   `json:"table"`
+	Module  string   `json:"module"`
+	Dialect string   `json:"dialect"`
+	Fields  []*Field `json:"fields"`
+	//Key primary key fields, empty when the table has no primary key
+	Key     []*Field `json:"key"`
+	Queries *Queries `json:"queries"`
+}
+
+//Queries statements of the generated repository, written as go string literals
+type Queries struct {
+	SelectAll   string `json:"select_all"`
+	SelectByKey string `json:"select_by_key,omitempty"`
+	Insert      string `json:"insert"`
+	Update      string `json:"update,omitempty"`
+	Delete      string `json:"delete,omitempty"`
+	//InsertFields and UpdateFields fields passed as arguments, in placeholder order. Update is followed by the key
+	InsertFields []*Field `json:"insert_fields"`
+	UpdateFields []*Field `json:"update_fields,omitempty"`
+	//Returning generated key read back with INSERT ... RETURNING
+	Returning *Field `json:"returning,omitempty"`
+	//LastInsertID generated integer key read back with LastInsertId
+	LastInsertID *Field `json:"last_insert_id,omitempty"`
+}
+
+//NewModel template data for table in the project with module path module.
+//Null is one of NullStyles(), dialect one of Dialects()
+func NewModel(table *Table, module, null, dialect string) (*Model, error) {
+	name := templates.Singular(templates.GoName(table.Name))
+	if name == "" || !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("table %s has no usable go name", table.Name)
+	}
+	var fields []*Field
+	seen := map[string]string{}
+	for _, x := range table.Columns {
+		goType, err := x.GoType(null)
+		if err != nil {
+			return nil, err
+		}
+		field := &Field{Name: templates.GoName(x.Name), Column: x.Name, GoType: goType, Generated: x.Generated}
+		if !token.IsIdentifier(field.Name) {
+			return nil, fmt.Errorf("table %s: column %s has no usable go name", table.Name, x.Name)
+		}
+		if other, ok := seen[field.Name]; ok {
+			return nil, fmt.Errorf("table %s: columns %s and %s are both %s in go", table.Name, other, x.Name, field.Name)
+		}
+		seen[field.Name] = x.Name
+		fields = append(fields, field)
+	}
+	return NewFieldModel(name, table.Name, module, dialect, fields, table.PrimaryKey)
+}
+
+//NewFieldModel template data for a row named name of table with the given fields, for rows that are not parsed
+//from a schema. Key names the primary key columns, dialect is one of Dialects()
+func NewFieldModel(name, table, module, dialect string, fields []*Field, key []string) (*Model, error) {
+	if !isDialect(dialect) {
+		return nil, fmt.Errorf("unknown dialect %s, available: %s", dialect, strings.Join(Dialects(), ", "))
+	}
+	m := &Model{
+		Name:    name,
+		Table:   table,
+		Module:  module,
+		Dialect: dialect,
+		Fields:  fields,
+	}
+	for _, x := range key {
+		field := m.field(x)
+		if field == nil {
+			return nil, fmt.Errorf("table %s has no key column %s", table, x)
+		}
+		field.Var = m.param(field)
+		m.Key = append(m.Key, field)
+	}
+	m.Queries = m.queries()
+	return m, nil
+}
+
+func (m *Model) field(column string) *Field {
+	for _, x := range m.Fields {
+		if strings.EqualFold(x.Column, column) {
+			return x
+		}
+	}
+	return nil
+}
+
+//Var name for a local variable holding a row, never a keyword or an imported package
+func (m *Model) Var() string {
+	name := lowerFirstWord(m.Name, templates.Singular(m.Table))
+	if token.IsKeyword(name) || reservedVars[name] {
+		name += "Row"
+	}
+	return name
+}
+
+//param name of the parameter for a key field
+func (m *Model) param(field *Field) string {
+	name := lowerFirstWord(field.Name, field.Column)
+	if token.IsKeyword(name) || reservedVars[name] || name == m.Var() {
+		name += "Key"
+	}
+	return name
+}
+
+//KeyName key fields joined for method names, FindByID or FindByOrderIDAndProductID
+func (m *Model) KeyName() string {
+	names := make([]string, 0, len(m.Key))
+	for _, x := range m.Key {
+		names = append(names, x.Name)
+	}
+	return strings.Join(names, "And")
+}
+
+//KeyParams key parameters of FindBy and Delete, "orderID int64, productID int64"
+func (m *Model) KeyParams() string {
+	params := make([]string, 0, len(m.Key))
+	for _, x := range m.Key {
+		params = append(params, x.Var+" "+x.GoType)
+	}
+	return strings.Join(params, ", ")
+}
+
+//KeyArgs key parameters passed on, "orderID, productID"
+func (m *Model) KeyArgs() string {
+	args := make([]string, 0, len(m.Key))
+	for _, x := range m.Key {
+		args = append(args, x.Var)
+	}
+	return strings.Join(args, ", ")
+}
+
+//Imports packages the domain struct needs, sorted
+func (m *Model) Imports() []string {
+	set := map[string]bool{}
+	for _, x := range m.Fields {
+		switch strings.TrimPrefix(x.GoType, "*") {
+		case "time.Time":
+			set["time"] = true
+		case "json.RawMessage":
+			set["encoding/json"] = true
+		}
+		if strings.HasPrefix(x.GoType, "sql.") {
+			set["database/sql"] = true
+		}
+	}
+	imports := make([]string, 0, len(set))
+	for x := range set {
+		imports = append(imports, x)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+//Objects files generated for the model, relative to the project root
+func (m *Model) Objects() []*wizard.Object {
+	return []*wizard.Object{
+		{
+			Name:     "domain/{{.Name | snake}}.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("repo/domain.go"),
+			Data:     m,
+		},
+		{
+			Name:     "domain/{{.Name | snake}}_repository.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("repo/repository.go"),
+			Data:     m,
+		},
+		{
+			Name:     "infrastructure/repositories/{{.Name | snake}}_sql_repository.go",
+			Type:     wizard.TypeFile,
+			Template: templates.Loader("repo/sql_repository.go"),
+			Data:     m,
+		},
+	}
+}
+
+//BaseRepositoryFile the database/sql Repository every generated repository embeds
+const BaseRepositoryFile = "infrastructure/repositories/repository.go"
+
+//BaseRepositoryObject BaseRepositoryFile for projects generated without a database
+func BaseRepositoryObject() *wizard.Object {
+	return &wizard.Object{
+		Name:     BaseRepositoryFile,
+		Type:     wizard.TypeFile,
+		Template: templates.Loader("database/sql_repository.go"),
+	}
+}
+
+func (m *Model) queries() *Queries {
+	q := &Queries{}
+	columns := make([]string, 0, len(m.Fields))
+	for _, x := range m.Fields {
+		columns = append(columns, m.quote(x.Column))
+	}
+	selectAll := "SELECT " + strings.Join(columns, ", ") + " FROM " + m.quote(m.Table)
+	var key []string
+	for i, x := range m.Key {
+		key = append(key, m.quote(x.Column)+" = "+m.placeholder(i+1))
+	}
+	if len(m.Key) > 0 {
+		order := make([]string, 0, len(m.Key))
+		for _, x := range m.Key {
+			order = append(order, m.quote(x.Column))
+		}
+		q.SelectAll = literal(selectAll + " ORDER BY " + strings.Join(order, ", "))
+		q.SelectByKey = literal(selectAll + " WHERE " + strings.Join(key, " AND "))
+		q.Delete = literal("DELETE FROM " + m.quote(m.Table) + " WHERE " + strings.Join(key, " AND "))
+	} else {
+		q.SelectAll = literal(selectAll)
+	}
+
+	var insertColumns, values []string
+	for _, x := range m.Fields {
+		if x.Generated {
+			continue
+		}
+		q.InsertFields = append(q.InsertFields, x)
+		insertColumns = append(insertColumns, m.quote(x.Column))
+		values = append(values, m.placeholder(len(values)+1))
+	}
+	insert := "INSERT INTO " + m.quote(m.Table)
+	switch {
+	case len(insertColumns) > 0:
+		insert += " (" + strings.Join(insertColumns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ")"
+	case m.Dialect == mysqlDialect:
+		insert += " () VALUES ()"
+	default:
+		insert += " DEFAULT VALUES"
+	}
+	if len(m.Key) == 1 && m.Key[0].Generated {
+		switch {
+		case m.Dialect == postgresDialect:
+			q.Returning = m.Key[0]
+			insert += " RETURNING " + m.quote(m.Key[0].Column)
+		case isInteger(m.Key[0].GoType):
+			q.LastInsertID = m.Key[0]
+		}
+	}
+	q.Insert = literal(insert)
+
+	if len(m.Key) > 0 {
+		var set []string
+		for _, x := range m.Fields {
+			if x.Generated || x.Var != "" {
+				continue
+			}
+			q.UpdateFields = append(q.UpdateFields, x)
+			set = append(set, m.quote(x.Column)+" = "+m.placeholder(len(set)+1))
+		}
+		if len(set) > 0 {
+			key = key[:0]
+			for i, x := range m.Key {
+				key = append(key, m.quote(x.Column)+" = "+m.placeholder(len(set)+i+1))
+			}
+			q.Update = literal("UPDATE " + m.quote(m.Table) + " SET " + strings.Join(set, ", ") + " WHERE " + strings.Join(key, " AND "))
+		}
+	}
+	return q
+}
+
+//quote an identifier when it is not a plain lower case name or is a reserved word
+func (m *Model) quote(name string) string {
+	if plainIdentifier.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	if m.Dialect == mysqlDialect {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (m *Model) placeholder(n int) string {
+	if m.Dialect == postgresDialect {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+//literal go string literal of a query or tag, a raw string unless s has a backtick
+func literal(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+//lowerFirstWord lower cases the first word of goName, the go name of name, keeping initialisms of the other words.
+//OrderID of order_id => orderID, URLPath of url_path => urlPath
+func lowerFirstWord(goName, name string) string {
+	words := common.SplitWords(name)
+	if len(words) == 0 || len(words[0]) > len(goName) {
+		return templates.Camel(goName)
+	}
+	return strings.ToLower(goName[:len(words[0])]) + goName[len(words[0]):]
+}
+
+func isInteger(goType string) bool {
+	return strings.HasPrefix(goType, "int") || strings.HasPrefix(goType, "uint")
+}
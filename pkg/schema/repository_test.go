@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/AkronimBlack/stock/pkg/wizard"
+)
+
+//TestGeneratedFiles the files gen:repo writes for the fixtures parse, for every dialect and null style,
+//and the struct tags read back as the column names
+func TestGeneratedFiles(t *testing.T) {
+	for _, fixture := range []string{"mysql.sql", "postgres.sql"} {
+		src, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables, err := Parse(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, dialect := range Dialects() {
+			for _, null := range NullStyles() {
+				t.Run(fixture+"/"+dialect+"/"+null, func(t *testing.T) {
+					objects := []*wizard.Object{BaseRepositoryObject()}
+					columns := map[string][]string{}
+					for _, table := range tables {
+						model, err := NewModel(table, "example.com/acme/shop", null, dialect)
+						if err != nil {
+							t.Fatal(err)
+						}
+						for _, x := range model.Fields {
+							columns[model.Name] = append(columns[model.Name], x.Column)
+						}
+						objects = append(objects, model.Objects()...)
+					}
+					config := wizard.NewConfig("shop", "example.com/acme/shop", "acme", nil)
+					config.OutputDir = t.TempDir()
+					entries, err := wizard.PlanObjects(config, objects, true)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, x := range entries {
+						file, err := goparser.ParseFile(token.NewFileSet(), x.Path, x.Content, goparser.AllErrors)
+						if err != nil {
+							t.Errorf("%s does not parse: %v\n%s", x.Path, err, x.Content)
+							continue
+						}
+						for name, tags := range structTags(file) {
+							if want := columns[name]; !reflect.DeepEqual(tags, want) {
+								t.Errorf("%s: %s db tags %q, want %q", x.Path, name, tags, want)
+							}
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+//structTags db tags of the fields of every struct declared in file, by struct name
+func structTags(file *ast.File) map[string][]string {
+	tags := map[string][]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		s, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, x := range s.Fields.List {
+			if x.Tag == nil {
+				continue
+			}
+			tag, err := strconv.Unquote(x.Tag.Value)
+			if err != nil {
+				tag = err.Error()
+			}
+			tags[spec.Name.Name] = append(tags[spec.Name.Name], reflect.StructTag(tag).Get("db"))
+		}
+		return false
+	})
+	return tags
+}
@@ -0,0 +1,74 @@
+-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+--
+-- Host: 127.0.0.1    Database: shop
+-- ------------------------------------------------------
+-- Server version	8.0.36
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET NAMES utf8mb4 */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+
+--
+-- Table structure for table `customers`
+--
+
+DROP TABLE IF EXISTS `customers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `customers` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(100) DEFAULT NULL COMMENT 'shown on; invoices',
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `balance` decimal(10,2) NOT NULL DEFAULT '0.00',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  `full_name` varchar(357) GENERATED ALWAYS AS (concat(`name`,_utf8mb4' <',`email`,_utf8mb4'>')) VIRTUAL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `customers_email_unique` (`email`),
+  KEY `customers_name_index` (`name`(10))
+) ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `order_items`
+--
+
+DROP TABLE IF EXISTS `order_items`;
+CREATE TABLE `order_items` (
+  `order_id` bigint unsigned NOT NULL,
+  `product_id` int NOT NULL,
+  `quantity` smallint unsigned NOT NULL DEFAULT '1',
+  `note` text,
+  PRIMARY KEY (`order_id`,`product_id`) USING BTREE,
+  KEY `order_items_product_id_index` (`product_id`),
+  CONSTRAINT `order_items_order_id_foreign` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+--
+-- Table structure for table `audit log`
+--
+
+DROP TABLE IF EXISTS `audit log`;
+CREATE TABLE `audit log` (
+  `event` varchar(50) NOT NULL,
+  `key` varchar(32) DEFAULT NULL,
+  `payload` json DEFAULT NULL,
+  `logged``at` datetime(6) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+--
+-- Temporary view structure for view `active_customers`
+--
+
+DROP TABLE IF EXISTS `active_customers`;
+/*!50001 DROP VIEW IF EXISTS `active_customers`*/;
+/*!50001 CREATE VIEW `active_customers` AS SELECT 1 AS `id`*/;
+
+DELIMITER ;;
+/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER `customers_touch` BEFORE UPDATE ON `customers` FOR EACH ROW SET NEW.updated_at = NOW() */;;
+DELIMITER ;
+
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+
+-- Dump completed on 2024-03-09 23:05:07
@@ -0,0 +1,177 @@
+--
+-- PostgreSQL database dump
+--
+
+-- Dumped from database version 16.2
+-- Dumped by pg_dump version 16.2
+
+SET statement_timeout = 0;
+SET lock_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+SELECT pg_catalog.set_config('search_path', '', false);
+SET check_function_bodies = false;
+
+--
+-- Name: touch_updated_at(); Type: FUNCTION; Schema: public; Owner: shop
+--
+
+CREATE FUNCTION public.touch_updated_at() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.updated_at := now();
+    RETURN NEW;
+END;
+$$;
+
+
+ALTER FUNCTION public.touch_updated_at() OWNER TO shop;
+
+--
+-- Name: greeting(text); Type: FUNCTION; Schema: public; Owner: shop
+--
+
+CREATE FUNCTION public.greeting(name text) RETURNS text
+    LANGUAGE sql
+    AS $body$ SELECT 'hello; ' || name || $$;$$ $body$;
+
+SET default_tablespace = '';
+
+SET default_table_access_method = heap;
+
+--
+-- Name: customers; Type: TABLE; Schema: public; Owner: shop
+--
+
+CREATE TABLE public.customers (
+    id bigint NOT NULL,
+    email character varying(255) NOT NULL,
+    "Display Name" text,
+    tags text[] DEFAULT '{}'::text[] NOT NULL,
+    balance numeric(10,2) DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp without time zone,
+    CONSTRAINT customers_email_check CHECK ((email IS NOT NULL))
+);
+
+
+ALTER TABLE public.customers OWNER TO shop;
+
+--
+-- Name: customers_id_seq; Type: SEQUENCE; Schema: public; Owner: shop
+--
+
+CREATE SEQUENCE public.customers_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER SEQUENCE public.customers_id_seq OWNER TO shop;
+
+ALTER SEQUENCE public.customers_id_seq OWNED BY public.customers.id;
+
+--
+-- Name: order_items; Type: TABLE; Schema: public; Owner: shop
+--
+
+CREATE TABLE public.order_items (
+    order_id bigint NOT NULL,
+    product_id integer NOT NULL,
+    quantity integer DEFAULT 1 NOT NULL
+);
+
+
+ALTER TABLE public.order_items OWNER TO shop;
+
+--
+-- Name: orders; Type: TABLE; Schema: public; Owner: shop
+--
+
+CREATE TABLE public.orders (
+    id integer NOT NULL,
+    customer_id bigint,
+    status character varying(20) DEFAULT 'new'::character varying NOT NULL,
+    status_label text GENERATED ALWAYS AS (upper((status)::text)) STORED
+);
+
+
+ALTER TABLE public.orders OWNER TO shop;
+
+--
+-- Name: orders_id_seq; Type: SEQUENCE; Schema: public; Owner: shop
+--
+
+ALTER TABLE public.orders ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.orders_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+--
+-- Name: audit_log; Type: TABLE; Schema: public; Owner: shop
+--
+
+CREATE UNLOGGED TABLE public.audit_log (
+    event text NOT NULL,
+    payload jsonb
+);
+
+
+ALTER TABLE public.audit_log OWNER TO shop;
+
+--
+-- Name: customers id; Type: DEFAULT; Schema: public; Owner: shop
+--
+
+ALTER TABLE ONLY public.customers ALTER COLUMN id SET DEFAULT nextval('public.customers_id_seq'::regclass);
+
+--
+-- Name: customers customers_pkey; Type: CONSTRAINT; Schema: public; Owner: shop
+--
+
+ALTER TABLE ONLY public.customers
+    ADD CONSTRAINT customers_pkey PRIMARY KEY (id);
+
+--
+-- Name: order_items order_items_pkey; Type: CONSTRAINT; Schema: public; Owner: shop
+--
+
+ALTER TABLE ONLY public.order_items
+    ADD CONSTRAINT order_items_pkey PRIMARY KEY (order_id, product_id);
+
+--
+-- Name: orders orders_pkey; Type: CONSTRAINT; Schema: public; Owner: shop
+--
+
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+
+--
+-- Name: orders_status_idx; Type: INDEX; Schema: public; Owner: shop
+--
+
+CREATE INDEX orders_status_idx ON public.orders USING btree (status);
+
+--
+-- Name: customers customers_touch; Type: TRIGGER; Schema: public; Owner: shop
+--
+
+CREATE TRIGGER customers_touch BEFORE UPDATE ON public.customers FOR EACH ROW EXECUTE FUNCTION public.touch_updated_at();
+
+--
+-- Name: orders orders_customer_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: shop
+--
+
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_customer_id_fkey FOREIGN KEY (customer_id) REFERENCES public.customers(id) ON DELETE SET NULL;
+
+--
+-- PostgreSQL database dump complete
+--
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	//NullPointer nullable columns are pointers, NULL is nil and json null
+	NullPointer = "pointer"
+	//NullSQL nullable columns are sql.NullString, sql.NullInt64 and the other database/sql null types
+	NullSQL = "sql"
+)
+
+//NullStyles how nullable columns can be represented
+func NullStyles() []string {
+	return []string{NullPointer, NullSQL}
+}
+
+//goTypes go type of every known column type, types that are not listed are strings
+var goTypes = map[string]string{
+	"boolean": "bool", "bool": "bool",
+
+	"tinyint": "int8", "smallint": "int16", "int2": "int16", "smallserial": "int16", "serial2": "int16", "year": "int16",
+	"mediumint": "int32", "int": "int32", "integer": "int32", "int4": "int32", "serial": "int32", "serial4": "int32",
+	"bigint": "int64", "int8": "int64", "bigserial": "int64", "serial8": "int64",
+
+	"real": "float32", "float4": "float32", "float": "float64", "float8": "float64", "double": "float64",
+	"double precision": "float64", "decimal": "float64", "numeric": "float64", "dec": "float64", "fixed": "float64",
+
+	"date": "time.Time", "datetime": "time.Time", "timestamp": "time.Time", "timestamptz": "time.Time",
+	"timestamp with time zone": "time.Time", "timestamp without time zone": "time.Time",
+
+	"json": "json.RawMessage", "jsonb": "json.RawMessage",
+
+	"bytea": "[]byte", "blob": "[]byte", "tinyblob": "[]byte", "mediumblob": "[]byte", "longblob": "[]byte",
+	"binary": "[]byte", "varbinary": "[]byte", "bit": "[]byte",
+}
+
+//unsignedTypes go type of unsigned integer columns
+var unsignedTypes = map[string]string{
+	"int8": "uint8", "int16": "uint16", "int32": "uint32", "int64": "uint64",
+}
+
+//nullTypes database/sql null type wrapping a go type
+var nullTypes = map[string]string{
+	"string": "sql.NullString", "bool": "sql.NullBool", "time.Time": "sql.NullTime",
+	"int8": "sql.NullInt32", "int16": "sql.NullInt32", "int32": "sql.NullInt32", "uint8": "sql.NullInt32", "uint16": "sql.NullInt32",
+	"int64": "sql.NullInt64", "uint32": "sql.NullInt64", "uint64": "sql.NullInt64",
+	"float32": "sql.NullFloat64", "float64": "sql.NullFloat64",
+}
+
+//BaseType go type of the column ignoring nullability. tinyint(1) is a bool, the mysql convention for booleans,
+//postgres arrays and unknown types are strings
+func (c *Column) BaseType() string {
+	if c.Type == "tinyint" && c.Size == "1" {
+		return "bool"
+	}
+	goType, ok := goTypes[c.Type]
+	if !ok {
+		return "string"
+	}
+	if unsigned, ok := unsignedTypes[goType]; ok && c.Unsigned {
+		return unsigned
+	}
+	return goType
+}
+
+//GoType go type of the column, nullable columns are represented in the null style
+func (c *Column) GoType(null string) (string, error) {
+	goType := c.BaseType()
+	if !c.Nullable || goType == "[]byte" {
+		//a nil slice is NULL already
+		return goType, nil
+	}
+	switch null {
+	case NullPointer:
+		return "*" + goType, nil
+	case NullSQL:
+		if nullType, ok := nullTypes[goType]; ok {
+			return nullType, nil
+		}
+		return "*" + goType, nil
+	}
+	return "", fmt.Errorf("unknown null style %s, available: %s", null, strings.Join(NullStyles(), ", "))
+}
@@ -0,0 +1,318 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/AkronimBlack/stock/common"
+	"gopkg.in/yaml.v2"
+)
+
+//delimsHeader optional first line of a template declaring its own delimiters, eg. "# stock:delims [[ ]]".
+//The line may be a comment (#, //, --, ;, /* */ or <!-- -->) so the template stays valid in its own language
+var delimsHeader = regexp.MustCompile(`^\s*(?:#|//|--|;|/\*|<!--)?\s*stock:delims\s+(\S+)\s+(\S+)\s*(?:\*/|-->)?\s*\r?\n`)
+
+//Parse parses a template with the function library. Delimiters declared in a stock:delims header win over
+//the delims argument, default delimiters are used when both are empty
+func Parse(name string, content []byte, delims ...string) (*template.Template, error) {
+	left, right := "", ""
+	if len(delims) == 2 {
+		left, right = delims[0], delims[1]
+	}
+	if m := delimsHeader.FindSubmatch(content); m != nil {
+		left, right = string(m[1]), string(m[2])
+		content = content[len(m[0]):]
+	}
+	return template.New(name).Delims(left, right).Funcs(FuncMap()).Parse(string(content))
+}
+
+//Render loads the named template (see Get) and executes it against data
+func Render(name string, data interface{}) ([]byte, error) {
+	content, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := Parse(name, content)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//FuncMap functions available to every template
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"camel":      Camel,
+		"pascal":     Pascal,
+		"snake":      Snake,
+		"kebab":      Kebab,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      Title,
+		"goName":     GoName,
+		"plural":     Plural,
+		"singular":   Singular,
+		"quote":      quote,
+		"squote":     func(v interface{}) string { return "'" + fmt.Sprint(v) + "'" },
+		"default":    defaultValue,
+		"env":        os.Getenv,
+		"now":        time.Now,
+		"date":       func(layout string, t time.Time) string { return t.Format(layout) },
+		"indent":     indent,
+		"nindent":    func(spaces int, v string) string { return "\n" + indent(spaces, v) },
+		"toYaml":     toYaml,
+		"toJson":     toJSON,
+		"trim":       strings.TrimSpace,
+		"replace":    func(old, new, v string) string { return strings.ReplaceAll(v, old, new) },
+		"join":       func(sep string, v []string) string { return strings.Join(v, sep) },
+		"split":      func(sep, v string) []string { return strings.Split(v, sep) },
+		"contains":   func(substr, v string) bool { return strings.Contains(v, substr) },
+		"hasPrefix":  func(prefix, v string) bool { return strings.HasPrefix(v, prefix) },
+		"hasSuffix":  func(suffix, v string) bool { return strings.HasSuffix(v, suffix) },
+		"importPath": importPath,
+		"list":       func(v ...string) []string { return v },
+	}
+}
+
+func words(v string) []string {
+	return common.SplitWords(v)
+}
+
+//Camel "order item" => "orderItem"
+func Camel(v string) string {
+	parts := words(v)
+	for i := 1; i < len(parts); i++ {
+		parts[i] = capitalize(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+//Pascal "order item" => "OrderItem"
+func Pascal(v string) string {
+	parts := words(v)
+	for i := range parts {
+		parts[i] = capitalize(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+//Title upper cases the first letter of every word and keeps everything else, "order item" => "Order Item"
+func Title(v string) string {
+	runes := []rune(v)
+	start := true
+	for i, r := range runes {
+		if start && unicode.IsLetter(r) {
+			runes[i] = unicode.ToUpper(r)
+		}
+		start = unicode.IsSpace(r) || r == '-'
+	}
+	return string(runes)
+}
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"db":   "DB",
+	"html": "HTML",
+	"http": "HTTP",
+	"id":   "ID",
+	"ip":   "IP",
+	"json": "JSON",
+	"sql":  "SQL",
+	"uri":  "URI",
+	"url":  "URL",
+	"uuid": "UUID",
+	"xml":  "XML",
+}
+
+//GoName exported go identifier following the initialisms convention, "user_id" => "UserID"
+func GoName(v string) string {
+	parts := words(v)
+	for i, x := range parts {
+		if initialism, ok := initialisms[x]; ok {
+			parts[i] = initialism
+			continue
+		}
+		parts[i] = capitalize(x)
+	}
+	return strings.Join(parts, "")
+}
+
+//Snake "OrderItem" => "order_item"
+func Snake(v string) string {
+	return strings.Join(words(v), "_")
+}
+
+//Kebab "OrderItem" => "order-item"
+func Kebab(v string) string {
+	return strings.Join(words(v), "-")
+}
+
+func capitalize(v string) string {
+	if v == "" {
+		return v
+	}
+	runes := []rune(v)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+var irregularPlurals = map[string]string{
+	"person": "people",
+	"child":  "children",
+	"man":    "men",
+	"woman":  "women",
+	"mouse":  "mice",
+	"datum":  "data",
+}
+
+//Plural english plural of the last word, "category" => "categories", "OrderItem" => "OrderItems"
+func Plural(v string) string {
+	start := lastWordStart(v)
+	for singular, plural := range irregularPlurals {
+		if strings.ToLower(v[start:]) == singular {
+			return v[:start] + applyCase(v[start:], plural)
+		}
+	}
+	lower := strings.ToLower(v)
+	switch {
+	case lower == "":
+		return v
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		return v[:len(v)-1] + matchCase(v[len(v)-1:], "ies")
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return v + matchCase(v[len(v)-1:], "es")
+	}
+	return v + matchCase(v[len(v)-1:], "s")
+}
+
+//Singular reverses Plural, "categories" => "category"
+func Singular(v string) string {
+	start := lastWordStart(v)
+	for singular, plural := range irregularPlurals {
+		if strings.ToLower(v[start:]) == plural {
+			return v[:start] + applyCase(v[start:], singular)
+		}
+	}
+	lower := strings.ToLower(v)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return v[:len(v)-3] + matchCase(v[len(v)-3:], "y")
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"),
+		strings.HasSuffix(lower, "uses") && !strings.HasSuffix(lower, "ouses"):
+		return v[:len(v)-2]
+	case strings.HasSuffix(lower, "ss"):
+		return v
+	case strings.HasSuffix(lower, "s"):
+		return v[:len(v)-1]
+	}
+	return v
+}
+
+//lastWordStart byte index where the last word of a name starts, "OrderPerson" => 5
+func lastWordStart(v string) int {
+	for i := len(v) - 1; i > 0; i-- {
+		c, prev := v[i], v[i-1]
+		if prev == '_' || prev == '-' || prev == ' ' || prev == '.' || prev == '/' {
+			return i
+		}
+		if c >= 'A' && c <= 'Z' && prev >= 'a' && prev <= 'z' {
+			return i
+		}
+	}
+	return 0
+}
+
+//applyCase gives replacement the case of word, "Person" => "People", "CHILD" => "CHILDREN"
+func applyCase(word, replacement string) string {
+	switch {
+	case strings.ToUpper(word) == word:
+		return strings.ToUpper(replacement)
+	case unicode.IsUpper([]rune(word)[0]):
+		return capitalize(replacement)
+	}
+	return replacement
+}
+
+//matchCase upper cases suffix when the text it replaces is upper case
+func matchCase(replaced, suffix string) string {
+	if replaced != "" && strings.ToUpper(replaced) == replaced && strings.ToLower(replaced) != replaced {
+		return strings.ToUpper(suffix)
+	}
+	return suffix
+}
+
+func quote(v interface{}) string {
+	return fmt.Sprintf("%q", fmt.Sprint(v))
+}
+
+//defaultValue returns value unless it is empty, then fallback. Used as {{.Port | default "8080"}}
+func defaultValue(fallback interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || value[0] == nil {
+		return fallback
+	}
+	switch v := value[0].(type) {
+	case string:
+		if v == "" {
+			return fallback
+		}
+	case bool:
+		if !v {
+			return fallback
+		}
+	case int:
+		if v == 0 {
+			return fallback
+		}
+	case []string:
+		if len(v) == 0 {
+			return fallback
+		}
+	}
+	return value[0]
+}
+
+func indent(spaces int, v string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(v, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func toYaml(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+//importPath joins a module path with package directories, {{importPath .FullName "domain"}} => github.com/x/y/domain
+func importPath(module string, elem ...string) string {
+	return path.Join(append([]string{module}, elem...)...)
+}
@@ -0,0 +1,79 @@
+package templates
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseDelimsHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"hash", "# stock:delims [[ ]]\n"},
+		{"line comment", "// stock:delims [[ ]]\n"},
+		{"sql comment", "-- stock:delims [[ ]]\n"},
+		{"semicolon", "; stock:delims [[ ]]\n"},
+		{"block comment", "/* stock:delims [[ ]] */\n"},
+		{"html comment", "<!-- stock:delims [[ ]] -->\n"},
+		{"no comment", "stock:delims [[ ]]\n"},
+		{"crlf", "# stock:delims [[ ]]\r\n"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			content := x.header + "{{.Name}} [[.Name]]"
+			tmpl, err := Parse(x.name, []byte(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err = tmpl.Execute(&buf, map[string]string{"Name": "order"}); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := buf.String(), "{{.Name}} order"; got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseDelimsArgument(t *testing.T) {
+	tmpl, err := Parse("delims", []byte("<% .Name %> {{.Name}}"), "<%", "%>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, map[string]string{"Name": "order"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "order {{.Name}}"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{"camel", Camel, "order item", "orderItem"},
+		{"pascal", Pascal, "order_item", "OrderItem"},
+		{"snake", Snake, "OrderItem", "order_item"},
+		{"kebab", Kebab, "OrderItem", "order-item"},
+		{"title", Title, "order item", "Order Item"},
+		{"title keeps case", Title, "an HTTP api", "An HTTP Api"},
+		{"title hyphen", Title, "read-only field", "Read-Only Field"},
+		{"title keeps spacing", Title, " two  spaces", " Two  Spaces"},
+		{"plural", Plural, "category", "categories"},
+		{"singular", Singular, "people", "person"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			if got := x.fn(x.in); got != x.want {
+				t.Errorf("%s(%q) = %q, want %q", x.name, x.in, got, x.want)
+			}
+		})
+	}
+}
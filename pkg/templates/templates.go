@@ -0,0 +1,167 @@
+package templates
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+//go:embed files files/.env.tmpl files/.gitignore.tmpl
+var builtin embed.FS
+
+const (
+	//SourceProject template overridden in the project templates directory
+	SourceProject = "project"
+	//SourceUser template overridden in ~/.stock/templates
+	SourceUser = "user"
+	//SourceBuiltin template shipped with stock
+	SourceBuiltin = "builtin"
+
+	extension = ".tmpl"
+	root      = "files"
+)
+
+//ProjectDirName project level templates directory, relative to the project directory. It is kept apart from
+//.stock, where stock regenerate keeps the copies of generated files
+const ProjectDirName = ".stock-templates"
+
+//projectRoot directory the project templates directory is looked up in, the working directory when empty
+var projectRoot string
+
+//SetProjectRoot sets the directory project templates are looked up in, eg. the --dir or --output of a command
+func SetProjectRoot(dir string) {
+	projectRoot = dir
+}
+
+//ProjectDir project level templates directory
+func ProjectDir() string {
+	return filepath.Join(projectRoot, ProjectDirName)
+}
+
+//Template a template and where it was loaded from
+type Template struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Path    string `json:"path"`
+	Content []byte `json:"-"`
+}
+
+//location a directory searched for template overrides
+type location struct {
+	source string
+	dir    string
+}
+
+//UserDir ~/.stock/templates
+func UserDir() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".stock", "templates"), nil
+}
+
+//overrides directories searched before the built-in templates, first match wins
+func overrides() []location {
+	locations := []location{{source: SourceProject, dir: ProjectDir()}}
+	if dir, err := UserDir(); err == nil {
+		locations = append(locations, location{source: SourceUser, dir: dir})
+	}
+	return locations
+}
+
+//Get loads a template by name (eg. gin/main.go). The project templates directory is checked first,
+//then ~/.stock/templates and last the built-in templates
+func Get(name string) (*Template, error) {
+	for _, x := range overrides() {
+		p := filepath.Join(x.dir, filepath.FromSlash(name)+extension)
+		content, err := ioutil.ReadFile(p)
+		if err == nil {
+			return &Template{Name: name, Source: x.source, Path: p, Content: content}, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return Builtin(name)
+}
+
+//Builtin loads the template shipped with stock, ignoring any overrides
+func Builtin(name string) (*Template, error) {
+	p := path.Join(root, name+extension)
+	content, err := builtin.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template %s does not exist", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Template{Name: name, Source: SourceBuiltin, Path: p, Content: content}, nil
+}
+
+//Load content of the named template, see Get for the lookup order
+func Load(name string) ([]byte, error) {
+	t, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.Content, nil
+}
+
+//Loader returns a function loading the named template, it is resolved every time it is called
+func Loader(name string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return Load(name)
+	}
+}
+
+//List every built-in template resolved to the source it would be loaded from
+func List() ([]*Template, error) {
+	var names []string
+	err := fs.WalkDir(builtin, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(p, root+"/"), extension))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	list := make([]*Template, 0, len(names))
+	for _, name := range names {
+		t, err := Get(name)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+//Eject copies the built-in template into dir so it can be customized. Existing files are never overwritten
+func Eject(name, dir string) (string, error) {
+	t, err := Builtin(name)
+	if err != nil {
+		return "", err
+	}
+	p := filepath.Join(dir, filepath.FromSlash(name)+extension)
+	if _, err = os.Stat(p); err == nil {
+		return "", fmt.Errorf("%s already exists", p)
+	}
+	if err = os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return "", err
+	}
+	return p, ioutil.WriteFile(p, t.Content, 0644)
+}
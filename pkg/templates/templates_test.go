@@ -0,0 +1,63 @@
+package templates
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetProjectOverride(t *testing.T) {
+	dir := t.TempDir()
+	SetProjectRoot(dir)
+	defer SetProjectRoot("")
+
+	builtin, err := Get("Dockerfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if builtin.Source != SourceBuiltin && builtin.Source != SourceUser {
+		t.Errorf("source %s without an override", builtin.Source)
+	}
+
+	//a generated file below .stock, where regenerate keeps its copies, is not an override
+	copied := filepath.Join(dir, ".stock", "templates", "Dockerfile.tmpl")
+	if err = os.MkdirAll(filepath.Dir(copied), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(copied, []byte("copy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if x, _ := Get("Dockerfile"); x.Source == SourceProject {
+		t.Errorf("%s used as a project template", copied)
+	}
+
+	override := filepath.Join(dir, ProjectDirName, "Dockerfile.tmpl")
+	if err = os.MkdirAll(filepath.Dir(override), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(override, []byte("FROM scratch\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	x, err := Get("Dockerfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Source != SourceProject || x.Path != override || string(x.Content) != "FROM scratch\n" {
+		t.Errorf("got %s %s %q, want the project override %s", x.Source, x.Path, x.Content, override)
+	}
+}
+
+func TestEject(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Eject("Makefile", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != filepath.Join(dir, "Makefile.tmpl") {
+		t.Errorf("ejected to %s", p)
+	}
+	if _, err = Eject("Makefile", dir); err == nil {
+		t.Error("second eject overwrote the ejected template")
+	}
+}
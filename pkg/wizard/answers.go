@@ -0,0 +1,56 @@
+package wizard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//LoadOptions reads wizard answers from a .json, .yaml or .yml file, unknown keys are an error in both formats
+func LoadOptions(path string) (*Options, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	opts := &Options{}
+	switch answersFormat(path) {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(opts)
+	default:
+		err = yaml.UnmarshalStrict(b, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading answers file %s: %w", path, err)
+	}
+	return opts, nil
+}
+
+//Save writes options to path so the same run can be replayed with make:app --answers
+func (o *Options) Save(path string) error {
+	var b []byte
+	var err error
+	switch answersFormat(path) {
+	case "json":
+		b, err = json.MarshalIndent(o, "", "  ")
+	default:
+		b, err = yaml.Marshal(o)
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+func answersFormat(path string) string {
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return "json"
+	}
+	return "yaml"
+}
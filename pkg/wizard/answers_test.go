@@ -0,0 +1,247 @@
+package wizard
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadOptions(t *testing.T) {
+	want := &Options{
+		FullName:   "example.com/acme/shop",
+		Framework:  chiFramework,
+		Transports: []string{transportHTTP, transportAMQP},
+		Database:   postgresDatabase,
+		Modules:    []string{metricsModule},
+		License:    mitLicense,
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    *Options
+		wantErr string
+	}{
+		{
+			name:    "shop.yaml",
+			content: "full_name: example.com/acme/shop\nframework: chi\ntransports: [http, amqp]\ndatabase: postgres\nmodules: [metrics]\nlicense: MIT\n",
+			want:    want,
+		},
+		{
+			name:    "shop.yml",
+			content: "full_name: example.com/acme/shop\nframework: chi\ntransports: [http, amqp]\ndatabase: postgres\nmodules: [metrics]\nlicense: MIT\n",
+			want:    want,
+		},
+		{
+			name:    "shop.json",
+			content: `{"full_name": "example.com/acme/shop", "framework": "chi", "transports": ["http", "amqp"], "database": "postgres", "modules": ["metrics"], "license": "MIT"}`,
+			want:    want,
+		},
+		{
+			name:    "SHOP.JSON",
+			content: `{"full_name": "example.com/acme/shop", "framework": "chi", "transports": ["http", "amqp"], "database": "postgres", "modules": ["metrics"], "license": "MIT"}`,
+			want:    want,
+		},
+		{
+			name:    "unknown.yaml",
+			content: "full_name: example.com/acme/shop\nframwork: chi\n",
+			wantErr: "framwork",
+		},
+		{
+			name:    "unknown.json",
+			content: `{"full_name": "example.com/acme/shop", "framwork": "chi"}`,
+			wantErr: "framwork",
+		},
+		{
+			name:    "output.json",
+			content: `{"full_name": "example.com/acme/shop", "OutputDir": "/tmp"}`,
+			wantErr: "OutputDir",
+		},
+		{
+			name:    "invalid.json",
+			content: `{"full_name": `,
+			wantErr: "reading answers file",
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), x.name)
+			writeFile(t, path, x.content)
+			got, err := LoadOptions(path)
+			if x.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, x.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, x.want) {
+				t.Errorf("got %+v, want %+v", got, x.want)
+			}
+		})
+	}
+}
+
+//TestSave a saved run replays the same answers, whatever the format
+func TestSave(t *testing.T) {
+	opts := NewOptionsFromName("example.com/acme/shop", echoFramework)
+	opts.Transports = []string{transportHTTP, transportGRPC}
+	opts.Database = sqliteDatabase
+	opts.Migrations = true
+	opts.Modules = []string{cacheModule, authModule}
+	opts.Contributing = true
+	opts.OutputDir = "/tmp/shop-output"
+	for _, name := range []string{"answers.yaml", "answers.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := opts.Save(path); err != nil {
+				t.Fatal(err)
+			}
+			if content := readFile(t, path); strings.Contains(content, opts.OutputDir) || strings.Contains(content, ConflictFail) {
+				t.Errorf("where the run writes is saved with the answers\n%s", content)
+			}
+			got, err := LoadOptions(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = got.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			got.OutputDir, got.ConflictPolicy = opts.OutputDir, opts.ConflictPolicy
+			if !reflect.DeepEqual(got, opts) {
+				t.Errorf("got %+v, want %+v", got, opts)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *Options
+		want *Options
+	}{
+		{
+			name: "defaults",
+			opts: &Options{FullName: " example.com/acme/shop "},
+			want: &Options{
+				ProjectName:    "shop",
+				Maintainer:     "acme",
+				Framework:      ginFramework,
+				FullName:       "example.com/acme/shop",
+				Archetype:      restArchetype,
+				Transports:     []string{transportHTTP},
+				Database:       noDatabase,
+				License:        proprietaryLicense,
+				Year:           2024,
+				GoVersion:      "1.16",
+				ConflictPolicy: ConflictFail,
+			},
+		},
+		{
+			name: "archetype defaults",
+			opts: &Options{FullName: "example.com/acme/jobs", Archetype: workerArchetype},
+			want: &Options{
+				ProjectName:    "jobs",
+				Maintainer:     "acme",
+				Framework:      noFramework,
+				FullName:       "example.com/acme/jobs",
+				Archetype:      workerArchetype,
+				Transports:     []string{transportAMQP},
+				Database:       noDatabase,
+				License:        proprietaryLicense,
+				Year:           2024,
+				GoVersion:      "1.16",
+				ConflictPolicy: ConflictFail,
+			},
+		},
+		{
+			name: "answers are kept",
+			opts: &Options{
+				FullName:       "example.com/acme/shop",
+				ProjectName:    "store",
+				Maintainer:     "Acme Inc.",
+				Framework:      chiFramework,
+				Transports:     []string{transportGRPC},
+				Database:       mysqlDatabase,
+				License:        apacheLicense,
+				Year:           2021,
+				GoVersion:      "1.21",
+				ConflictPolicy: ConflictSkip,
+			},
+			want: &Options{
+				ProjectName:    "store",
+				Maintainer:     "Acme Inc.",
+				Framework:      chiFramework,
+				FullName:       "example.com/acme/shop",
+				Archetype:      restArchetype,
+				Transports:     []string{transportGRPC},
+				Database:       mysqlDatabase,
+				License:        apacheLicense,
+				Year:           2021,
+				GoVersion:      "1.21",
+				ConflictPolicy: ConflictSkip,
+			},
+		},
+		{
+			name: "no full name",
+			opts: &Options{Framework: chiFramework},
+			want: &Options{Framework: chiFramework},
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			if x.opts.Year == 0 && x.opts.FullName != "" {
+				//the current year is the default, fixed so the test does not depend on when it runs
+				x.opts.Year = 2024
+			}
+			x.opts.Complete()
+			if !reflect.DeepEqual(x.opts, x.want) {
+				t.Errorf("got %+v, want %+v", x.opts, x.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(o *Options)
+		wantErr string
+	}{
+		{"complete", func(o *Options) {}, ""},
+		{"no full name", func(o *Options) { o.FullName = "" }, "full name is required"},
+		{"no project name", func(o *Options) { o.ProjectName = "" }, "could not determine project name"},
+		{"unknown archetype", func(o *Options) { o.Archetype = "daemon" }, "unknown archetype daemon"},
+		{"unknown framework", func(o *Options) { o.Framework = "fiber" }, "unknown http framework fiber"},
+		{"framework of a worker", func(o *Options) { o.Archetype = workerArchetype }, "worker projects have no http server"},
+		{"unknown transport", func(o *Options) { o.Transports = []string{"kafka"} }, "unknown transport kafka"},
+		{"no transport", func(o *Options) { o.Transports = nil }, "at least one transport is required"},
+		{"unknown database", func(o *Options) { o.Database = "oracle" }, "unknown database oracle"},
+		{"migrations of mongodb", func(o *Options) { o.Database, o.Migrations = mongoDatabase, true }, "migrations need a sql database"},
+		{"migrations without a database", func(o *Options) { o.Migrations = true }, "migrations need a sql database"},
+		{"unknown module", func(o *Options) { o.Modules = []string{"tracing"} }, "unknown module tracing"},
+		{"unknown license", func(o *Options) { o.License = "GPL" }, "unknown license GPL"},
+		{"invalid go version", func(o *Options) { o.GoVersion = "go1.20" }, "invalid go version go1.20"},
+		{"old go version", func(o *Options) { o.GoVersion = "1.15" }, "go version 1.15 is too old"},
+		{"unknown conflict policy", func(o *Options) { o.ConflictPolicy = "merge" }, "unknown conflict policy merge"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			opts := NewOptionsFromName("example.com/acme/shop", "")
+			x.change(opts)
+			err := opts.Validate()
+			if x.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, x.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package wizard
+
+const (
+	restArchetype    = "rest"
+	workerArchetype  = "worker"
+	grpcArchetype    = "grpc"
+	cliArchetype     = "cli"
+	libraryArchetype = "library"
+)
+
+//Archetype a kind of project with its own blueprint, main template and Dockerfile.
+//Blueprint names the built-in blueprint in blueprints/, Transports are the transports the archetype is reached through
+//(nil when they are asked for), HTTP archetypes serve the chosen http framework and Service archetypes run in a container
+//configured through .env and can connect to a database
+type Archetype struct {
+	Name        string
+	Description string
+	Blueprint   string
+	Transports  []string
+	HTTP        bool
+	Service     bool
+}
+
+//archetypes is the single registry of archetypes, the order is the order offered by the wizard
+var archetypes = []*Archetype{
+	{
+		Name:        restArchetype,
+		Description: "REST api, an http server with optional grpc and amqp",
+		Blueprint:   "blueprints/rest.yaml",
+		HTTP:        true,
+		Service:     true,
+	},
+	{
+		Name:        workerArchetype,
+		Description: "AMQP worker consuming and publishing messages",
+		Blueprint:   "blueprints/worker.yaml",
+		Transports:  []string{transportAMQP},
+		Service:     true,
+	},
+	{
+		Name:        grpcArchetype,
+		Description: "gRPC service",
+		Blueprint:   "blueprints/grpc.yaml",
+		Transports:  []string{transportGRPC},
+		Service:     true,
+	},
+	{
+		Name:        cliArchetype,
+		Description: "Command line tool built with cobra",
+		Blueprint:   "blueprints/cli.yaml",
+		Transports:  []string{},
+	},
+	{
+		Name:        libraryArchetype,
+		Description: "Go library imported by other modules",
+		Blueprint:   "blueprints/library.yaml",
+		Transports:  []string{},
+	},
+}
+
+//Archetypes list of kinds of projects that can be generated
+func Archetypes() []string {
+	names := make([]string, 0, len(archetypes))
+	for _, x := range archetypes {
+		names = append(names, x.Name)
+	}
+	return names
+}
+
+//DefaultArchetype archetype used when none is given
+func DefaultArchetype() string {
+	return restArchetype
+}
+
+//ArchetypeByName registered archetype, nil if there is none with that name
+func ArchetypeByName(name string) *Archetype {
+	for _, x := range archetypes {
+		if x.Name == name {
+			return x
+		}
+	}
+	return nil
+}
+
+func isArchetype(name string) bool {
+	return ArchetypeByName(name) != nil
+}
+
+//Kind the selected archetype, nil when it is unknown
+func (o *Options) Kind() *Archetype {
+	return ArchetypeByName(o.Archetype)
+}
+
+//IsService reports if the project runs as a service, used by templates shared between archetypes
+//eg. {{if .IsService}}
+func (o *Options) IsService() bool {
+	kind := o.Kind()
+	return kind != nil && kind.Service
+}
+
+//sameTransports reports if a and b hold the same transports in any order
+func sameTransports(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := map[string]bool{}
+	for _, x := range a {
+		set[x] = true
+	}
+	for _, x := range b {
+		if !set[x] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package wizard
+
+import (
+	"go/parser"
+	"go/token"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+//TestArchetypes every archetype generates the files of its own blueprint, its packages and the modules it imports
+func TestArchetypes(t *testing.T) {
+	tests := []struct {
+		archetype  string
+		transports []string
+		files      []string
+		//packages package clause of every go file by directory
+		packages map[string]string
+		requires []string
+	}{
+		{
+			archetype:  workerArchetype,
+			transports: []string{transportAMQP},
+			files: []string{
+				".env", ".env.example", ".gitignore", ".stock.yaml", "Dockerfile", "LICENSE", "Makefile", "README.md",
+				"cmd/shop/main.go", "cmd/shop/main_test.go", "cmd/shop/serve.go", "docker-compose.yml", "docker/Dockerfile", "go.mod",
+				"infrastructure/transport/amqp/connection.go", "infrastructure/transport/amqp/consumer.go", "infrastructure/transport/amqp/publisher.go",
+			},
+			packages: map[string]string{"cmd/shop": "main", "infrastructure/transport/amqp": "amqptransport"},
+			requires: []string{"github.com/Azure/go-amqp", "github.com/joho/godotenv"},
+		},
+		{
+			archetype:  grpcArchetype,
+			transports: []string{transportGRPC},
+			files: []string{
+				".env", ".env.example", ".gitignore", ".stock.yaml", "Dockerfile", "LICENSE", "Makefile", "README.md",
+				"api/proto/buf.yaml", "api/proto/shop/v1/shop.proto", "buf.gen.yaml",
+				"cmd/shop/main.go", "cmd/shop/main_test.go", "cmd/shop/serve.go", "docker-compose.yml", "docker/Dockerfile", "go.mod",
+				"infrastructure/transport/grpc/server.go", "infrastructure/transport/grpc/shop_server.go",
+			},
+			packages: map[string]string{"cmd/shop": "main", "infrastructure/transport/grpc": "grpctransport"},
+			requires: []string{"github.com/joho/godotenv", "google.golang.org/grpc", "google.golang.org/protobuf"},
+		},
+		{
+			archetype:  cliArchetype,
+			transports: []string{},
+			files: []string{
+				".gitignore", ".stock.yaml", "LICENSE", "Makefile", "README.md",
+				"cmd/root.go", "cmd/version.go", "cmd/version_test.go", "docker/Dockerfile", "go.mod", "main.go",
+			},
+			packages: map[string]string{".": "main", "cmd": "cmd"},
+			requires: []string{"github.com/spf13/cobra"},
+		},
+		{
+			archetype:  libraryArchetype,
+			transports: []string{},
+			files:      []string{".gitignore", ".stock.yaml", "LICENSE", "Makefile", "README.md", "doc.go", "example_test.go", "go.mod", "shop.go"},
+			packages:   map[string]string{".": "shop"},
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.archetype, func(t *testing.T) {
+			opts := &Options{FullName: "example.com/acme/shop", Archetype: x.archetype}
+			files := planFiles(t, opts)
+			if opts.Framework != noFramework {
+				t.Errorf("framework %s, want %s", opts.Framework, noFramework)
+			}
+			if !reflect.DeepEqual(opts.Transports, x.transports) {
+				t.Errorf("transports %v, want %v", opts.Transports, x.transports)
+			}
+
+			var names []string
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, x.files) {
+				t.Errorf("files\n%q\nwant\n%q", names, x.files)
+			}
+			if !strings.Contains(files[".stock.yaml"], "\nblueprint: "+x.archetype+"\n") {
+				t.Errorf(".stock.yaml does not record the %s blueprint\n%s", x.archetype, files[".stock.yaml"])
+			}
+
+			for name, content := range files {
+				if path.Ext(name) != ".go" {
+					continue
+				}
+				f, err := parser.ParseFile(token.NewFileSet(), name, content, parser.PackageClauseOnly)
+				if err != nil {
+					t.Errorf("%s does not parse: %v", name, err)
+					continue
+				}
+				want := x.packages[path.Dir(name)]
+				//external tests of a package
+				if got := strings.TrimSuffix(f.Name.Name, "_test"); got != want {
+					t.Errorf("%s is package %s, want %s", name, f.Name.Name, want)
+				}
+			}
+			parseGoFiles(t, files)
+
+			mod, err := modfile.Parse("go.mod", []byte(files["go.mod"]), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var requires []string
+			for _, y := range mod.Require {
+				requires = append(requires, y.Mod.Path)
+			}
+			if !reflect.DeepEqual(requires, x.requires) {
+				t.Errorf("go.mod requires %v, want %v", requires, x.requires)
+			}
+		})
+	}
+}
+
+//TestServiceArchetypeDatabase the worker and grpc archetypes connect to a database like the rest archetype
+func TestServiceArchetypeDatabase(t *testing.T) {
+	for _, archetype := range []string{workerArchetype, grpcArchetype} {
+		t.Run(archetype, func(t *testing.T) {
+			opts := &Options{FullName: "example.com/acme/shop", Archetype: archetype, Database: postgresDatabase, Migrations: true}
+			files := planFiles(t, opts)
+			parseGoFiles(t, files)
+			for _, name := range []string{
+				"infrastructure/repositories/database.go", "infrastructure/repositories/repository.go",
+				"migrations/migrations.go", "migrations/000001_init.up.sql", "migrations/000001_init.down.sql",
+			} {
+				if _, ok := files[name]; !ok {
+					t.Errorf("%s is not generated", name)
+				}
+			}
+			if !strings.Contains(files["cmd/shop/main.go"], "repositories.Connect(repositories.DatabaseConfigFromEnv())") {
+				t.Errorf("main.go does not connect to the database\n%s", files["cmd/shop/main.go"])
+			}
+			if !strings.Contains(files["docker-compose.yml"], "shop_db:") {
+				t.Errorf("docker-compose.yml has no database service\n%s", files["docker-compose.yml"])
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package wizard
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed blueprints/*.yaml
+var builtinBlueprints embed.FS
+
+//Blueprint declarative description of the generated project tree
+type Blueprint struct {
+	Name    string    `json:"name" yaml:"name"`
+	Version string    `json:"version" yaml:"version"`
+	Objects []*Object `json:"objects" yaml:"objects"`
+}
+
+//DefaultBlueprint the built-in REST api layout
+func DefaultBlueprint() (*Blueprint, error) {
+	return ArchetypeBlueprint(DefaultArchetype())
+}
+
+//ArchetypeBlueprint the built-in layout of an archetype
+func ArchetypeBlueprint(name string) (*Blueprint, error) {
+	kind := ArchetypeByName(name)
+	if kind == nil {
+		return nil, fmt.Errorf("unknown archetype %s, available: %s", name, strings.Join(Archetypes(), ", "))
+	}
+	b, err := builtinBlueprints.ReadFile(kind.Blueprint)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlueprint(b, "yaml", "")
+}
+
+//LoadBlueprint reads a blueprint from a .yaml, .yml or .json file. Template sources are relative to the blueprint
+func LoadBlueprint(path string) (*Blueprint, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	blueprint, err := parseBlueprint(b, answersFormat(path), filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("blueprint %s: %w", path, err)
+	}
+	return blueprint, nil
+}
+
+func parseBlueprint(b []byte, format, dir string) (*Blueprint, error) {
+	blueprint := &Blueprint{}
+	var err error
+	switch format {
+	case "json":
+		err = json.Unmarshal(b, blueprint)
+	default:
+		err = yaml.UnmarshalStrict(b, blueprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(blueprint.Objects) == 0 {
+		return nil, fmt.Errorf("blueprint %s has no objects", blueprint.Name)
+	}
+	for _, x := range blueprint.Objects {
+		if err = x.resolve(dir); err != nil {
+			return nil, err
+		}
+	}
+	return blueprint, nil
+}
+
+//loadBlueprint blueprint for opts, from opts.Blueprint or the built-in one of the archetype, with the files of the
+//selected feature modules
+func loadBlueprint(opts *Options) (*Blueprint, error) {
+	var blueprint *Blueprint
+	var err error
+	if opts.Blueprint != "" {
+		blueprint, err = LoadBlueprint(opts.Blueprint)
+	} else {
+		blueprint, err = ArchetypeBlueprint(opts.Archetype)
+	}
+	if err != nil {
+		return nil, err
+	}
+	objects, err := moduleObjects(opts)
+	if err != nil {
+		return nil, err
+	}
+	if root := blueprint.Objects[0]; len(blueprint.Objects) == 1 && root.Type == TypeDir {
+		//module files are relative to the project root
+		root.SubObjects = append(root.SubObjects, objects...)
+	} else {
+		blueprint.Objects = append(blueprint.Objects, objects...)
+	}
+	return blueprint, nil
+}
+
+//resolve checks the object and hooks up its template function, dir is where template sources are read from
+func (o *Object) resolve(dir string) error {
+	if o.Name == "" {
+		return fmt.Errorf("object without a name")
+	}
+	if o.Type == "" {
+		o.Type = TypeDir
+		if o.TemplateName != "" || o.Source != "" {
+			o.Type = TypeFile
+		}
+	}
+	switch o.Type {
+	case TypeDir:
+		if o.TemplateName != "" || o.Source != "" {
+			return fmt.Errorf("directory %s can not have a template", o.Name)
+		}
+	case TypeFile:
+		if len(o.SubObjects) > 0 {
+			return fmt.Errorf("file %s can not have children", o.Name)
+		}
+	default:
+		return fmt.Errorf("object %s has unknown type %s", o.Name, o.Type)
+	}
+
+	if len(o.Delims) != 0 && len(o.Delims) != 2 {
+		return fmt.Errorf("object %s delims must be a left and a right delimiter", o.Name)
+	}
+
+	switch {
+	case o.TemplateName != "" && o.Source != "":
+		return fmt.Errorf("object %s has both a template and a source", o.Name)
+	case o.TemplateName != "":
+		x, ok := templateMap[o.TemplateName]
+		if !ok {
+			return fmt.Errorf("template %s for %s does not exist", o.TemplateName, o.Name)
+		}
+		o.Template = x
+	case o.Source != "":
+		source := o.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(dir, source)
+		}
+		b, err := ioutil.ReadFile(source)
+		if err != nil {
+			return fmt.Errorf("source for %s: %w", o.Name, err)
+		}
+		o.Template = func() ([]byte, error) { return b, nil }
+	}
+
+	for _, x := range o.SubObjects {
+		if err := x.resolve(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//included evaluates the when condition against the data the object is rendered with, objects without a condition
+//are always included
+func (o *Object) included(config *Config) (bool, error) {
+	if strings.TrimSpace(o.When) == "" {
+		return true, nil
+	}
+	condition, err := template.New(o.Name).Funcs(templates.FuncMap()).Parse("{{if " + o.When + "}}true{{end}}")
+	if err != nil {
+		return false, fmt.Errorf("condition for %s: %w", o.Name, err)
+	}
+	var buf bytes.Buffer
+	if err = condition.Execute(&buf, o.data(config)); err != nil {
+		return false, fmt.Errorf("condition for %s: %w", o.Name, err)
+	}
+	return buf.String() == "true", nil
+}
@@ -0,0 +1,31 @@
+package wizard
+
+import (
+	"testing"
+)
+
+func TestIncluded(t *testing.T) {
+	config := NewConfig("project", "example.com/acme/project", "acme", &Options{Framework: "gin"})
+	tests := []struct {
+		name   string
+		object *Object
+		want   bool
+	}{
+		{"no condition", &Object{Name: "a"}, true},
+		{"config data", &Object{Name: "a", When: `eq .Framework "gin"`}, true},
+		{"config data false", &Object{Name: "a", When: `eq .Framework "chi"`}, false},
+		{"own data", &Object{Name: "a", When: `eq .Framework "chi"`, Data: &Options{Framework: "chi"}}, true},
+		{"own data false", &Object{Name: "a", When: `eq .Framework "gin"`, Data: &Options{Framework: "chi"}}, false},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			got, err := x.object.included(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != x.want {
+				t.Errorf("included = %v, want %v", got, x.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AkronimBlack/stock/pkg/diff"
+	"github.com/AlecAivazis/survey/v2"
+)
+
+const (
+	//ConflictSkip keep the existing file
+	ConflictSkip = "skip"
+	//ConflictOverwrite replace the existing file with the rendered template
+	ConflictOverwrite = "overwrite"
+	//ConflictBackup move the existing file to {name}.{timestamp}.bak and write the rendered template
+	ConflictBackup = "backup"
+	//ConflictPrompt ask for every file that differs
+	ConflictPrompt = "prompt"
+	//ConflictFail stop generation on the first file that differs
+	ConflictFail = "fail"
+)
+
+//ConflictPolicies list of available policies for files that already exist and differ from the rendered template
+func ConflictPolicies() []string {
+	return []string{ConflictSkip, ConflictOverwrite, ConflictBackup, ConflictPrompt, ConflictFail}
+}
+
+func isConflictPolicy(policy string) bool {
+	for _, x := range ConflictPolicies() {
+		if x == policy {
+			return true
+		}
+	}
+	return false
+}
+
+//ConflictError returned by the fail policy
+type ConflictError struct {
+	Path string
+	Diff string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s already exists and differs from the generated file", e.Path)
+}
+
+const (
+	actionCreate    = "create"
+	actionKeep      = "keep"
+	actionOverwrite = "overwrite"
+	actionBackup    = "backup"
+)
+
+//resolveConflict decides what happens with path when content is generated for it. Nothing is changed on disk
+func resolveConflict(path string, content []byte, policy string) (string, error) {
+	existing, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return actionCreate, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(existing, content) {
+		return actionKeep, nil
+	}
+
+	changes := diff.Unified(path, path+" (generated)", existing, content)
+	fmt.Print(changes)
+	switch policy {
+	case ConflictSkip:
+		fmt.Println("Skipping", path)
+		return actionKeep, nil
+	case ConflictOverwrite:
+		return actionOverwrite, nil
+	case ConflictBackup:
+		return actionBackup, nil
+	case ConflictPrompt:
+		overwrite := false
+		err = survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("%s already exists and differs. Overwrite?", path),
+		}, &overwrite)
+		if err != nil || !overwrite {
+			return actionKeep, err
+		}
+		return actionOverwrite, nil
+	case ConflictFail:
+		return "", &ConflictError{Path: path, Diff: changes}
+	}
+	return "", fmt.Errorf("unknown conflict policy %s, available: %s", policy, strings.Join(ConflictPolicies(), ", "))
+}
+
+func backupPath(path string) string {
+	return fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102150405"))
+}
@@ -0,0 +1,113 @@
+package wizard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveConflict(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	writeFile(t, existing, "old\n")
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		policy  string
+		want    string
+		wantErr string
+	}{
+		{"new file", filepath.Join(dir, "new.txt"), "new\n", ConflictFail, actionCreate, ""},
+		{"same content", existing, "old\n", ConflictFail, actionKeep, ""},
+		{"skip", existing, "new\n", ConflictSkip, actionKeep, ""},
+		{"overwrite", existing, "new\n", ConflictOverwrite, actionOverwrite, ""},
+		{"backup", existing, "new\n", ConflictBackup, actionBackup, ""},
+		{"fail", existing, "new\n", ConflictFail, "", "existing.txt already exists and differs from the generated file"},
+		{"unknown policy", existing, "new\n", "merge", "", "unknown conflict policy merge"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			got, err := resolveConflict(x.path, []byte(x.content), x.policy)
+			if x.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, x.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != x.want {
+				t.Errorf("action = %s, want %s", got, x.want)
+			}
+		})
+	}
+	if content := readFile(t, existing); content != "old\n" {
+		t.Errorf("resolving changed the file: %q", content)
+	}
+}
+
+//TestConflictPolicies generates a project, changes its README.md and generates it again with every policy
+func TestConflictPolicies(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantReadme string
+		wantBackup bool
+		wantErr    bool
+	}{
+		{policy: ConflictSkip, wantReadme: "changed\n"},
+		{policy: ConflictOverwrite},
+		{policy: ConflictBackup, wantBackup: true},
+		{policy: ConflictFail, wantReadme: "changed\n", wantErr: true},
+	}
+	for _, x := range tests {
+		t.Run(x.policy, func(t *testing.T) {
+			opts := NewOptionsFromName("example.com/acme/shop", "chi")
+			opts.OutputDir = t.TempDir()
+			if err := Execute(opts); err != nil {
+				t.Fatal(err)
+			}
+			readme := filepath.Join(opts.OutputDir, "shop", "README.md")
+			generated := readFile(t, readme)
+			writeFile(t, readme, "changed\n")
+
+			opts.ConflictPolicy = x.policy
+			err := Execute(opts)
+			var conflict *ConflictError
+			if x.wantErr {
+				if !errors.As(err, &conflict) || conflict.Path != readme {
+					t.Fatalf("error = %v, want a conflict of %s", err, readme)
+				}
+				if !strings.Contains(conflict.Diff, "-changed\n") {
+					t.Errorf("the conflict has no diff of the file\n%s", conflict.Diff)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			want := x.wantReadme
+			if want == "" {
+				want = generated
+			}
+			if got := readFile(t, readme); got != want {
+				t.Errorf("README.md = %q, want %q", got, want)
+			}
+			backups, err := filepath.Glob(readme + ".*.bak")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(backups) == 1; got != x.wantBackup {
+				t.Fatalf("backups %v, want one: %v", backups, x.wantBackup)
+			}
+			if x.wantBackup && readFile(t, backups[0]) != "changed\n" {
+				t.Errorf("the backup is not the changed file")
+			}
+			if _, err = os.Stat(filepath.Join(opts.OutputDir, "shop", "Makefile")); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
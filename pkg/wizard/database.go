@@ -0,0 +1,126 @@
+package wizard
+
+const (
+	postgresDatabase = "postgres"
+	mysqlDatabase    = "mysql"
+	mongoDatabase    = "mongodb"
+	sqliteDatabase   = "sqlite"
+	noDatabase       = "none"
+)
+
+//Database everything the generator needs to know about a database.
+//Module is the driver that identifies the database in an existing go.mod, its version is in the dependency catalog under
+//database:{name}. Port the port the database listens on (empty when it runs in process),
+//ConnectionTemplate names the template for infrastructure/repositories/database.go opening the connection and
+//RepositoryTemplate the template for the base repository generated repositories embed
+type Database struct {
+	Name               string
+	Module             string
+	Port               string
+	ConnectionTemplate string
+	RepositoryTemplate string
+}
+
+//databases is the single registry of databases, the order is the order offered by the wizard
+var databases = []*Database{
+	{
+		Name:               postgresDatabase,
+		Module:             "github.com/lib/pq",
+		Port:               "5432",
+		ConnectionTemplate: "database/sql.go",
+		RepositoryTemplate: "database/sql_repository.go",
+	},
+	{
+		Name:               mysqlDatabase,
+		Module:             "github.com/go-sql-driver/mysql",
+		Port:               "3306",
+		ConnectionTemplate: "database/sql.go",
+		RepositoryTemplate: "database/sql_repository.go",
+	},
+	{
+		Name:               mongoDatabase,
+		Module:             "go.mongodb.org/mongo-driver",
+		Port:               "27017",
+		ConnectionTemplate: "database/mongo.go",
+		RepositoryTemplate: "database/mongo_repository.go",
+	},
+	{
+		//pure go driver, the Dockerfile builds with CGO_ENABLED=0
+		Name:               sqliteDatabase,
+		Module:             "modernc.org/sqlite",
+		ConnectionTemplate: "database/sql.go",
+		RepositoryTemplate: "database/sql_repository.go",
+	},
+	{
+		Name: noDatabase,
+	},
+}
+
+//Databases list of databases a project can be generated for
+func Databases() []string {
+	names := make([]string, 0, len(databases))
+	for _, x := range databases {
+		names = append(names, x.Name)
+	}
+	return names
+}
+
+//DefaultDatabase database used when none is given
+func DefaultDatabase() string {
+	return noDatabase
+}
+
+//DatabaseByName registered database, nil if there is none with that name
+func DatabaseByName(name string) *Database {
+	for _, x := range databases {
+		if x.Name == name {
+			return x
+		}
+	}
+	return nil
+}
+
+func isDatabase(name string) bool {
+	return DatabaseByName(name) != nil
+}
+
+//SQLDatabases databases used through database/sql
+func SQLDatabases() []string {
+	var names []string
+	for _, x := range databases {
+		if x.SQL() {
+			names = append(names, x.Name)
+		}
+	}
+	return names
+}
+
+//SQL reports if the database is used through database/sql
+func (d *Database) SQL() bool {
+	return d.ConnectionTemplate == "database/sql.go"
+}
+
+//Mongo reports if the database is mongodb, used through the mongo driver
+func (d *Database) Mongo() bool {
+	return d.Name == mongoDatabase
+}
+
+//DB selected database, nil when the project has none. Used by templates, eg. {{with .DB}}{{.Port}}{{end}}
+func (o *Options) DB() *Database {
+	if db := DatabaseByName(o.Database); db != nil && db.Name != noDatabase {
+		return db
+	}
+	return nil
+}
+
+//HasDatabase reports if a database was selected, used by templates and blueprint conditions
+//eg. when: .HasDatabase
+func (o *Options) HasDatabase() bool {
+	return o.DB() != nil
+}
+
+//HasDatabaseService reports if the database runs as a docker compose service, sqlite runs in the service process
+func (o *Options) HasDatabaseService() bool {
+	db := o.DB()
+	return db != nil && db.Port != ""
+}
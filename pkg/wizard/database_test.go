@@ -0,0 +1,58 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+)
+
+//TestDatabases every database opens its connection with its own driver, is configured in .env and runs
+//as a docker compose service unless it runs in process
+func TestDatabases(t *testing.T) {
+	for _, db := range databases {
+		t.Run(db.Name, func(t *testing.T) {
+			opts := NewOptionsFromName("example.com/acme/shop", "")
+			opts.Database = db.Name
+			files := planFiles(t, opts)
+			parseGoFiles(t, files)
+
+			selected := db.Name != noDatabase
+			for _, name := range []string{"infrastructure/repositories/database.go", "infrastructure/repositories/repository.go"} {
+				if _, ok := files[name]; ok != selected {
+					t.Errorf("%s generated: %v, want %v", name, ok, selected)
+				}
+			}
+			if got := strings.Contains(files["cmd/shop/main.go"], "repositories.Connect(repositories.DatabaseConfigFromEnv())"); got != selected {
+				t.Errorf("main.go connects to the database: %v, want %v", got, selected)
+			}
+
+			service := db.Port != ""
+			contains := map[string][]string{
+				"docker-compose.yml": {"shop_db:", "DB_HOST: shop_db", "- " + db.Port + ":" + db.Port + "\n", "shop_db_data:"},
+				".env":               {"DB_HOST=127.0.0.1\n", "DB_PORT=" + db.Port + "\n", "DB_NAME=shop\n", "DB_USERNAME=", "DB_PASSWORD="},
+			}
+			for name, parts := range contains {
+				for _, x := range parts {
+					if got := strings.Contains(files[name], x); got != service {
+						t.Errorf("%s contains %q: %v, want %v", name, x, got, service)
+					}
+				}
+			}
+			if got := strings.Contains(files[".env"], "DB_PATH=shop.db\n"); got != (db.Name == sqliteDatabase) {
+				t.Errorf(".env contains DB_PATH: %v", got)
+			}
+
+			for _, x := range databases {
+				if x.Module == "" {
+					continue
+				}
+				want := x.Name == db.Name
+				if got := strings.Contains(files["go.mod"], "\t"+x.Module+" "); got != want {
+					t.Errorf("go.mod requires %s: %v, want %v", x.Module, got, want)
+				}
+				if got := strings.Contains(files["infrastructure/repositories/database.go"], `"`+x.Module); got != want {
+					t.Errorf("database.go imports %s: %v, want %v", x.Module, got, want)
+				}
+			}
+		})
+	}
+}
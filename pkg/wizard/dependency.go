@@ -0,0 +1,226 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	//BaseFeature every generated project needs its dependencies
+	BaseFeature = "base"
+	//minGoVersion generated projects embed files (go:embed) and use io/fs
+	minGoVersion = "1.16"
+	//toolFeature catalog entries of code generators that are modules of their own, they are never required by go.mod
+	toolFeature = "tool"
+)
+
+//Dependency a module required by go.mod of generated projects when Feature is enabled.
+//Features are BaseFeature, archetype:{name}, framework:{name}, transport:{name}, database:{name} and module:{name}, see Options.Features.
+//A module can be listed for several features
+type Dependency struct {
+	Module  string `json:"module" yaml:"module" mapstructure:"module"`
+	Version string `json:"version" yaml:"version" mapstructure:"version"`
+	Feature string `json:"feature,omitempty" yaml:"feature,omitempty" mapstructure:"feature"`
+}
+
+//dependencies is the catalog of modules generated code imports, overridden by the dependencies key of stock's config
+var dependencies = []*Dependency{
+	{Module: "github.com/joho/godotenv", Version: "v1.3.0", Feature: "archetype:" + restArchetype},
+	{Module: "github.com/joho/godotenv", Version: "v1.3.0", Feature: "archetype:" + workerArchetype},
+	{Module: "github.com/joho/godotenv", Version: "v1.3.0", Feature: "archetype:" + grpcArchetype},
+	{Module: "github.com/spf13/cobra", Version: "v1.5.0", Feature: "archetype:" + cliArchetype},
+
+	{Module: "github.com/gin-contrib/cors", Version: "v1.3.1", Feature: "framework:" + ginFramework},
+	{Module: "github.com/gin-gonic/gin", Version: "v1.6.3", Feature: "framework:" + ginFramework},
+	{Module: "github.com/gorilla/handlers", Version: "v1.5.1", Feature: "framework:" + gorillaFramework},
+	{Module: "github.com/gorilla/mux", Version: "v1.8.0", Feature: "framework:" + gorillaFramework},
+	{Module: "github.com/go-chi/chi/v5", Version: "v5.0.7", Feature: "framework:" + chiFramework},
+	{Module: "github.com/go-chi/cors", Version: "v1.2.0", Feature: "framework:" + chiFramework},
+	{Module: "github.com/labstack/echo/v4", Version: "v4.6.1", Feature: "framework:" + echoFramework},
+
+	{Module: "google.golang.org/grpc", Version: "v1.45.0", Feature: "transport:" + transportGRPC},
+	{Module: "google.golang.org/protobuf", Version: "v1.28.0", Feature: "transport:" + transportGRPC},
+	{Module: "github.com/Azure/go-amqp", Version: "v0.13.1", Feature: "transport:" + transportAMQP},
+	//v1.2.0 generates code for grpc.SupportPackageIsVersion7, newer releases need a newer google.golang.org/grpc
+	{Module: "google.golang.org/grpc/cmd/protoc-gen-go-grpc", Version: "v1.2.0", Feature: toolFeature},
+
+	{Module: "github.com/lib/pq", Version: "v1.10.9", Feature: "database:" + postgresDatabase},
+	{Module: "github.com/go-sql-driver/mysql", Version: "v1.7.1", Feature: "database:" + mysqlDatabase},
+	{Module: "go.mongodb.org/mongo-driver", Version: "v1.11.9", Feature: "database:" + mongoDatabase},
+	{Module: "modernc.org/sqlite", Version: "v1.20.4", Feature: "database:" + sqliteDatabase},
+
+	{Module: "github.com/prometheus/client_golang", Version: "v1.12.2", Feature: "module:" + metricsModule},
+	{Module: "github.com/go-redis/redis/v8", Version: "v8.11.5", Feature: "module:" + cacheModule},
+	{Module: "github.com/golang-jwt/jwt/v4", Version: "v4.5.0", Feature: "module:" + authModule},
+}
+
+//Tool a code generator generated projects install with go install. Package is installed at the catalog version
+//of Module so the code it generates compiles against the versions go.mod requires
+type Tool struct {
+	Package string
+	Module  string
+	Feature string
+}
+
+//tools code generators of the features, protoc-gen-go is released with the protobuf runtime
+var tools = []*Tool{
+	{Package: "google.golang.org/protobuf/cmd/protoc-gen-go", Module: "google.golang.org/protobuf", Feature: "transport:" + transportGRPC},
+	{Package: "google.golang.org/grpc/cmd/protoc-gen-go-grpc", Module: "google.golang.org/grpc/cmd/protoc-gen-go-grpc", Feature: "transport:" + transportGRPC},
+}
+
+//OverrideDependencies merges overrides into the catalog. A module already in the catalog gets the new version
+//(and is added for the feature when one is given), a new module is added for its feature, BaseFeature when it has none
+func OverrideDependencies(overrides []*Dependency) error {
+	for _, x := range overrides {
+		if err := module.CheckPath(x.Module); err != nil {
+			return fmt.Errorf("dependency %s: %w", x.Module, err)
+		}
+		if !semver.IsValid(x.Version) {
+			return fmt.Errorf("dependency %s: invalid version %q", x.Module, x.Version)
+		}
+		if existing := dependenciesOf(x.Module); len(existing) > 0 {
+			for _, y := range existing {
+				y.Version = x.Version
+			}
+			if x.Feature != "" && x.Feature != existing[0].Feature {
+				dependencies = append(dependencies, &Dependency{Module: x.Module, Version: x.Version, Feature: x.Feature})
+			}
+			continue
+		}
+		feature := x.Feature
+		if feature == "" {
+			feature = BaseFeature
+		}
+		dependencies = append(dependencies, &Dependency{Module: x.Module, Version: x.Version, Feature: feature})
+	}
+	return nil
+}
+
+//dependenciesOf every catalog entry of a module
+func dependenciesOf(path string) []*Dependency {
+	var found []*Dependency
+	for _, x := range dependencies {
+		if x.Module == path {
+			found = append(found, x)
+		}
+	}
+	return found
+}
+
+//Features every feature the answers enable, dependencies of these features are required by go.mod
+func (o *Options) Features() []string {
+	features := []string{BaseFeature, "archetype:" + o.Archetype}
+	if kind := o.Kind(); kind != nil && kind.HTTP {
+		features = append(features, "framework:"+o.Framework)
+	}
+	for _, x := range o.Transports {
+		features = append(features, "transport:"+x)
+	}
+	if o.Database != "" {
+		features = append(features, "database:"+o.Database)
+	}
+	for _, x := range o.Modules {
+		features = append(features, "module:"+x)
+	}
+	return features
+}
+
+//Requires dependencies of the enabled features, one per module with the highest version asked for, sorted by module
+func (o *Options) Requires() []*Dependency {
+	enabled := map[string]bool{}
+	for _, x := range o.Features() {
+		enabled[x] = true
+	}
+	byModule := map[string]*Dependency{}
+	for _, x := range dependencies {
+		if !enabled[x.Feature] {
+			continue
+		}
+		if existing, ok := byModule[x.Module]; ok && semver.Compare(existing.Version, x.Version) >= 0 {
+			continue
+		}
+		byModule[x.Module] = x
+	}
+	requires := make([]*Dependency, 0, len(byModule))
+	for _, x := range byModule {
+		requires = append(requires, x)
+	}
+	sort.Slice(requires, func(i, j int) bool { return requires[i].Module < requires[j].Module })
+	return requires
+}
+
+//Tools go install arguments of the code generators the enabled features need,
+//eg. google.golang.org/protobuf/cmd/protoc-gen-go@v1.28.0. Used by templates as {{range .Tools}}
+func (o *Options) Tools() []string {
+	enabled := map[string]bool{}
+	for _, x := range o.Features() {
+		enabled[x] = true
+	}
+	var installs []string
+	for _, x := range tools {
+		if enabled[x.Feature] {
+			installs = append(installs, x.Package+"@"+catalogVersion(x.Module))
+		}
+	}
+	return installs
+}
+
+//catalogVersion highest version of a module in the catalog
+func catalogVersion(path string) string {
+	version := ""
+	for _, x := range dependenciesOf(path) {
+		if version == "" || semver.Compare(x.Version, version) > 0 {
+			version = x.Version
+		}
+	}
+	return version
+}
+
+//GoMod go.mod of the generated project, written with the modfile package so it is always valid
+func (o *Options) GoMod() ([]byte, error) {
+	f := &modfile.File{}
+	if err := f.AddModuleStmt(o.FullName); err != nil {
+		return nil, err
+	}
+	if err := f.AddGoStmt(o.GoVersion); err != nil {
+		return nil, err
+	}
+	for _, x := range o.Requires() {
+		f.AddNewRequire(x.Module, x.Version, false)
+	}
+	f.SortBlocks()
+	f.Cleanup()
+	return f.Format()
+}
+
+//Tidy runs go mod tidy in dir so go.sum has the checksums of every dependency, go build refuses to build without them.
+//-e keeps going past packages that cannot be found yet, eg. the grpc code generated by buf
+func Tidy(dir string) error {
+	command := exec.Command("go", "mod", "tidy", "-e")
+	command.Dir = dir
+	if out, err := command.CombinedOutput(); err != nil {
+		return fmt.Errorf("go mod tidy: %w\n%s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+//DefaultGoVersion go version of go.mod when none is given
+func DefaultGoVersion() string {
+	return minGoVersion
+}
+
+func validateGoVersion(version string) error {
+	if !modfile.GoVersionRE.MatchString(version) {
+		return fmt.Errorf("invalid go version %s, eg. %s", version, DefaultGoVersion())
+	}
+	if semver.Compare("v"+version, "v"+minGoVersion) < 0 {
+		return fmt.Errorf("go version %s is too old, generated projects need at least %s", version, minGoVersion)
+	}
+	return nil
+}
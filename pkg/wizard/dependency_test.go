@@ -0,0 +1,253 @@
+package wizard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+//TestTools the code generators are pinned to the catalog, protoc-gen-go to the protobuf runtime go.mod requires
+func TestTools(t *testing.T) {
+	opts := NewOptionsFromName("example.com/acme/shop", "")
+	if tools := opts.Tools(); len(tools) != 0 {
+		t.Errorf("a http service needs no code generators: %v", tools)
+	}
+
+	opts.Transports = []string{transportHTTP, transportGRPC}
+	want := []string{
+		"google.golang.org/protobuf/cmd/protoc-gen-go@" + catalogVersion("google.golang.org/protobuf"),
+		"google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.2.0",
+	}
+	if got := opts.Tools(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, x := range opts.Requires() {
+		if x.Module == "google.golang.org/grpc/cmd/protoc-gen-go-grpc" {
+			t.Error("go.mod requires the grpc code generator")
+		}
+	}
+
+	files := planFiles(t, opts)
+	for _, name := range []string{"buf.gen.yaml", "README.md"} {
+		if strings.Contains(files[name], "@latest") {
+			t.Errorf("%s installs the latest code generators\n%s", name, files[name])
+		}
+		for _, x := range want {
+			if !strings.Contains(files[name], "go install "+x+"\n") {
+				t.Errorf("%s does not install %s\n%s", name, x, files[name])
+			}
+		}
+	}
+}
+
+//useCatalog replaces the dependency catalog for the test
+func useCatalog(t *testing.T, catalog ...*Dependency) {
+	saved := dependencies
+	t.Cleanup(func() { dependencies = saved })
+	dependencies = catalog
+}
+
+//requires module@version of every dependency, in order
+func requires(deps []*Dependency) []string {
+	var list []string
+	for _, x := range deps {
+		list = append(list, x.Module+"@"+x.Version)
+	}
+	return list
+}
+
+func TestRequires(t *testing.T) {
+	catalog := []*Dependency{
+		{Module: "example.com/z", Version: "v1.0.0", Feature: BaseFeature},
+		{Module: "example.com/a", Version: "v1.2.0", Feature: "framework:" + ginFramework},
+		{Module: "example.com/a", Version: "v1.10.0", Feature: "module:" + cacheModule},
+		{Module: "example.com/a", Version: "v1.9.0", Feature: "transport:" + transportGRPC},
+		{Module: "example.com/dup", Version: "v0.1.0", Feature: BaseFeature},
+		{Module: "example.com/dup", Version: "v0.1.0", Feature: "archetype:" + restArchetype},
+		{Module: "example.com/echo", Version: "v4.0.0", Feature: "framework:" + echoFramework},
+		{Module: "example.com/pq", Version: "v1.0.0", Feature: "database:" + postgresDatabase},
+		{Module: "example.com/tool", Version: "v2.0.0", Feature: toolFeature},
+	}
+	tests := []struct {
+		name string
+		opts *Options
+		want []string
+	}{
+		{
+			name: "base",
+			opts: &Options{Archetype: cliArchetype},
+			want: []string{"example.com/dup@v0.1.0", "example.com/z@v1.0.0"},
+		},
+		{
+			name: "duplicates removed",
+			opts: &Options{Archetype: restArchetype, Framework: echoFramework},
+			want: []string{"example.com/dup@v0.1.0", "example.com/echo@v4.0.0", "example.com/z@v1.0.0"},
+		},
+		{
+			name: "highest version",
+			opts: &Options{Archetype: restArchetype, Framework: ginFramework, Transports: []string{transportGRPC}, Modules: []string{cacheModule}},
+			want: []string{"example.com/a@v1.10.0", "example.com/dup@v0.1.0", "example.com/z@v1.0.0"},
+		},
+		{
+			name: "lower versions of other features",
+			opts: &Options{Archetype: restArchetype, Framework: ginFramework, Transports: []string{transportGRPC}},
+			want: []string{"example.com/a@v1.9.0", "example.com/dup@v0.1.0", "example.com/z@v1.0.0"},
+		},
+		{
+			//the framework of an archetype without http is not a feature
+			name: "framework without http",
+			opts: &Options{Archetype: workerArchetype, Framework: echoFramework, Database: postgresDatabase},
+			want: []string{"example.com/dup@v0.1.0", "example.com/pq@v1.0.0", "example.com/z@v1.0.0"},
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			useCatalog(t, catalog...)
+			if got := requires(x.opts.Requires()); !reflect.DeepEqual(got, x.want) {
+				t.Errorf("got %v, want %v", got, x.want)
+			}
+		})
+	}
+}
+
+func TestOverrideDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []*Dependency
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "new version of every entry",
+			overrides: []*Dependency{{Module: "example.com/a", Version: "v1.5.0"}},
+			want:      []string{"example.com/a@v1.5.0 " + BaseFeature, "example.com/a@v1.5.0 module:" + cacheModule, "example.com/b@v0.1.0 database:" + sqliteDatabase},
+		},
+		{
+			name:      "existing module for another feature",
+			overrides: []*Dependency{{Module: "example.com/b", Version: "v0.2.0", Feature: "module:" + authModule}},
+			want: []string{
+				"example.com/a@v1.0.0 " + BaseFeature, "example.com/a@v1.0.0 module:" + cacheModule,
+				"example.com/b@v0.2.0 database:" + sqliteDatabase, "example.com/b@v0.2.0 module:" + authModule,
+			},
+		},
+		{
+			name:      "existing module for its own feature",
+			overrides: []*Dependency{{Module: "example.com/b", Version: "v0.2.0", Feature: "database:" + sqliteDatabase}},
+			want:      []string{"example.com/a@v1.0.0 " + BaseFeature, "example.com/a@v1.0.0 module:" + cacheModule, "example.com/b@v0.2.0 database:" + sqliteDatabase},
+		},
+		{
+			name: "new modules",
+			overrides: []*Dependency{
+				{Module: "example.com/c", Version: "v3.0.0"},
+				{Module: "example.com/d", Version: "v0.0.1", Feature: "transport:" + transportAMQP},
+			},
+			want: []string{
+				"example.com/a@v1.0.0 " + BaseFeature, "example.com/a@v1.0.0 module:" + cacheModule, "example.com/b@v0.1.0 database:" + sqliteDatabase,
+				"example.com/c@v3.0.0 " + BaseFeature, "example.com/d@v0.0.1 transport:" + transportAMQP,
+			},
+		},
+		{
+			name:      "invalid path",
+			overrides: []*Dependency{{Module: "shop", Version: "v1.0.0"}},
+			wantErr:   "dependency shop: malformed module path",
+		},
+		{
+			name:      "invalid version",
+			overrides: []*Dependency{{Module: "example.com/a", Version: "1.0"}},
+			wantErr:   `dependency example.com/a: invalid version "1.0"`,
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			useCatalog(t,
+				&Dependency{Module: "example.com/a", Version: "v1.0.0", Feature: BaseFeature},
+				&Dependency{Module: "example.com/a", Version: "v1.0.0", Feature: "module:" + cacheModule},
+				&Dependency{Module: "example.com/b", Version: "v0.1.0", Feature: "database:" + sqliteDatabase},
+			)
+			err := OverrideDependencies(x.overrides)
+			if x.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, x.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, y := range dependencies {
+				got = append(got, y.Module+"@"+y.Version+" "+y.Feature)
+			}
+			if !reflect.DeepEqual(got, x.want) {
+				t.Errorf("got %q, want %q", got, x.want)
+			}
+		})
+	}
+}
+
+//TestGoMod go.mod is read back by modfile with the module, go version and requirements of the answers
+func TestGoMod(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *Options
+		requires []string
+		wantErr  string
+	}{
+		{
+			name:     "base",
+			opts:     &Options{FullName: "example.com/acme/shop", Archetype: cliArchetype, GoVersion: "1.16"},
+			requires: []string{"example.com/z@v1.0.0"},
+		},
+		{
+			name:     "features",
+			opts:     &Options{FullName: "shop", Archetype: restArchetype, Framework: ginFramework, Database: sqliteDatabase, GoVersion: "1.21"},
+			requires: []string{"example.com/gin@v1.6.3", "example.com/sqlite/v2@v2.0.0", "example.com/z@v1.0.0"},
+		},
+		{
+			name:    "invalid go version",
+			opts:    &Options{FullName: "example.com/acme/shop", Archetype: cliArchetype, GoVersion: "go1.16"},
+			wantErr: "go1.16",
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			useCatalog(t,
+				&Dependency{Module: "example.com/z", Version: "v1.0.0", Feature: BaseFeature},
+				&Dependency{Module: "example.com/gin", Version: "v1.6.3", Feature: "framework:" + ginFramework},
+				&Dependency{Module: "example.com/sqlite/v2", Version: "v2.0.0", Feature: "database:" + sqliteDatabase},
+			)
+			b, err := x.opts.GoMod()
+			if x.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, x.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := modfile.Parse("go.mod", b, nil)
+			if err != nil {
+				t.Fatalf("go.mod does not parse: %v\n%s", err, b)
+			}
+			if f.Module.Mod.Path != x.opts.FullName {
+				t.Errorf("module %s, want %s", f.Module.Mod.Path, x.opts.FullName)
+			}
+			if f.Go.Version != x.opts.GoVersion {
+				t.Errorf("go %s, want %s", f.Go.Version, x.opts.GoVersion)
+			}
+			var got []string
+			for _, y := range f.Require {
+				if y.Indirect {
+					t.Errorf("%s is required as indirect", y.Mod.Path)
+				}
+				got = append(got, y.Mod.String())
+			}
+			if !reflect.DeepEqual(got, x.requires) {
+				t.Errorf("requires %v, want %v\n%s", got, x.requires, b)
+			}
+		})
+	}
+}
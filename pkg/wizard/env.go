@@ -0,0 +1,60 @@
+package wizard
+
+import (
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+//EnvVar a key of .env and .env.example, Description is listed in the generated README
+type EnvVar struct {
+	Key         string
+	Value       string
+	Description string
+}
+
+//Env every key of .env in the order it is written: transports, database, migrations and the feature modules, each key once.
+//Used by the .env and README templates, eg. {{range .Env}}{{.Key}}={{.Value}}{{end}}
+func (o *Options) Env() []*EnvVar {
+	var env []*EnvVar
+	if o.HasTransport(transportHTTP) {
+		env = append(env,
+			&EnvVar{Key: "HOST", Value: "0.0.0.0", Description: "http host"},
+			&EnvVar{Key: "PORT", Value: "8080", Description: "http port"},
+		)
+	}
+	if o.HasTransport(transportAMQP) {
+		env = append(env,
+			&EnvVar{Key: "AMQP_HOST", Value: "127.0.0.1", Description: "broker host"},
+			&EnvVar{Key: "AMQP_PORT", Value: "5672", Description: "broker port"},
+			&EnvVar{Key: "AMQP_USERNAME", Value: "admin", Description: "broker user"},
+			&EnvVar{Key: "AMQP_PASSWORD", Value: "admin", Description: "broker password"},
+		)
+	}
+	if db := o.DB(); db != nil {
+		name := templates.Snake(o.Names().DockerName)
+		if db.Port == "" {
+			env = append(env, &EnvVar{Key: "DB_PATH", Value: name + ".db", Description: "database file"})
+		} else {
+			env = append(env,
+				&EnvVar{Key: "DB_HOST", Value: "127.0.0.1", Description: "database host"},
+				&EnvVar{Key: "DB_PORT", Value: db.Port, Description: "database port"},
+				&EnvVar{Key: "DB_NAME", Value: name, Description: "database name"},
+				&EnvVar{Key: "DB_USERNAME", Value: "user", Description: "database user"},
+				&EnvVar{Key: "DB_PASSWORD", Value: "secret", Description: "database password"},
+			)
+		}
+	}
+	if o.Migrations {
+		env = append(env, &EnvVar{Key: "DB_MIGRATE", Value: "true", Description: "run pending migrations on start"})
+	}
+	//a key the project already has is not taken from a module
+	keys := map[string]bool{}
+	for _, x := range env {
+		keys[x.Key] = true
+	}
+	for _, x := range o.Contributions().Env {
+		if !keys[x.Key] {
+			env = append(env, x)
+		}
+	}
+	return env
+}
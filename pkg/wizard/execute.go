@@ -1,11 +1,15 @@
 package wizard
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"log"
+	"go/format"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
-	"text/template"
+	"time"
 
 	"github.com/AkronimBlack/stock/common"
 	"github.com/AkronimBlack/stock/pkg/templates"
@@ -16,43 +20,77 @@ const (
 	TypeDir  = "dir"
 	//NamePlaceholder is a string the builder will look for and replace with name of full-name of the project
 	NamePlaceholder = "{app_name}"
-
-	ginFramework     = "gin"
-	gorillaFramework = "gorilla"
-	noFramework      = "none"
 )
 
-var templateMap = map[string]func() []byte{
-	"main.go":            templates.GinTemplate,
-	"main_test.go":       templates.MainTestTemplate,
-	"Dockerfile":         templates.DockerfileTemplate,
-	"Dockerfile.dev":     templates.DockerfileDevTemplate,
-	"docker-compose.yml": templates.DockerComposeTemplate,
-	"go.mod":             goModTemplate,
-	".env":               templates.EnvTemplate,
-	".env.example":       templates.EnvTemplate,
-	".gitignore":         templates.GitIgnoreTemplate,
+var templateMap = map[string]func() ([]byte, error){
+	"main.go":                  mainTemplate,
+	"main_test.go":             templates.Loader("main_test.go"),
+	"serve.go":                 templates.Loader("serve.go"),
+	"Dockerfile":               templates.Loader("Dockerfile"),
+	"Dockerfile.dev":           templates.Loader("Dockerfile.dev"),
+	"docker-compose.yml":       templates.Loader("docker-compose.yml"),
+	"go.mod":                   goModTemplate,
+	".env":                     templates.Loader(".env"),
+	".env.example":             templates.Loader(".env"),
+	".gitignore":               templates.Loader(".gitignore"),
+	"grpc/service.proto":       templates.Loader("grpc/service.proto"),
+	"grpc/service_server.go":   templates.Loader("grpc/service_server.go"),
+	"grpc/server.go":           templates.Loader("grpc/server.go"),
+	"buf.yaml":                 templates.Loader("grpc/buf.yaml"),
+	"buf.gen.yaml":             templates.Loader("grpc/buf.gen.yaml"),
+	"amqp/connection.go":       templates.Loader("amqp/connection.go"),
+	"amqp/publisher.go":        templates.Loader("amqp/publisher.go"),
+	"amqp/consumer.go":         templates.Loader("amqp/consumer.go"),
+	"database.go":              databaseTemplate(func(d *Database) string { return d.ConnectionTemplate }),
+	"repository.go":            databaseTemplate(func(d *Database) string { return d.RepositoryTemplate }),
+	"migrations/migrations.go": templates.Loader("migrations/migrations.go"),
+	"migrations/up.sql":        templates.Loader("migrations/up.sql"),
+	"migrations/down.sql":      templates.Loader("migrations/down.sql"),
+	"Makefile":                 templates.Loader("Makefile"),
+	"README.md":                templates.Loader("README.md"),
+	"CONTRIBUTING.md":          templates.Loader("CONTRIBUTING.md"),
+	"LICENSE":                  licenseTemplate,
+	"worker/main.go":           templates.Loader("worker/main.go"),
+	"grpc/main.go":             templates.Loader("grpc/main.go"),
+	"cli/main.go":              templates.Loader("cli/main.go"),
+	"cli/root.go":              templates.Loader("cli/root.go"),
+	"cli/version.go":           templates.Loader("cli/version.go"),
+	"cli/version_test.go":      templates.Loader("cli/version_test.go"),
+	"library/doc.go":           templates.Loader("library/doc.go"),
+	"library/library.go":       templates.Loader("library/library.go"),
+	"library/example_test.go":  templates.Loader("library/example_test.go"),
+	"modules/metrics.go":       templates.Loader("modules/metrics.go"),
+	"modules/cache.go":         templates.Loader("modules/cache.go"),
+	"modules/auth.go":          templates.Loader("modules/auth.go"),
+	"modules/auth_test.go":     templates.Loader("modules/auth_test.go"),
 }
 
 var executeOptions *Options
 
-//HTTPFrameworks list of available framework templates
-func HTTPFrameworks() []string {
-	return []string{ginFramework, gorillaFramework, noFramework}
-}
-
-//DefaultHTTPFramework list of available framework templates
-func DefaultHTTPFramework() string {
-	return ginFramework
+//Execute validates the options and builds the project scaffold
+func Execute(opts *Options) error {
+	config, err := prepare(opts)
+	if err != nil {
+		return err
+	}
+	common.LogJson(config)
+	objects, err := projectObjects(config, opts)
+	if err != nil {
+		return err
+	}
+	return Generate(config, objects)
 }
 
-func Execute(opts *Options) {
+func prepare(opts *Options) (*Config, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	executeOptions = opts
 	config := NewConfig(opts.ProjectName, opts.FullName, opts.Maintainer, opts)
-	common.LogJson(config)
-	for _, x := range getObjectMap() {
-		x.Build(config)
-	}
+	config.OutputDir = opts.OutputDir
+	config.ConflictPolicy = opts.ConflictPolicy
+	config.KeepInvalid = opts.KeepInvalid
+	return config, nil
 }
 
 func NewOptions(projectName, Maintainer, framework, fullName string) *Options {
@@ -64,49 +102,188 @@ func NewOptions(projectName, Maintainer, framework, fullName string) *Options {
 	}
 }
 
+//NewOptionsFromName derives project name and maintainer from the full module name
+func NewOptionsFromName(fullName, framework string) *Options {
+	opts := &Options{
+		Framework: framework,
+		FullName:  fullName,
+	}
+	opts.Complete()
+	return opts
+}
+
 type Options struct {
-	ProjectName string `json:"project_name"`
-	Maintainer  string `json:"maintainer"`
-	Framework   string `json:"framework"`
-	FullName    string `json:"full_name"`
+	ProjectName string `json:"project_name" yaml:"project_name"`
+	Maintainer  string `json:"maintainer" yaml:"maintainer"`
+	Framework   string `json:"framework" yaml:"framework"`
+	FullName    string `json:"full_name" yaml:"full_name"`
+	//Archetype kind of project, one of Archetypes()
+	Archetype string `json:"archetype" yaml:"archetype"`
+	//Transports any of http, grpc and amqp, see Transports()
+	Transports []string `json:"transports" yaml:"transports"`
+	//Database one of Databases(), none generates no database container or repository code
+	Database string `json:"database" yaml:"database"`
+	//Migrations generates the migrations directory and runner, only for databases used through database/sql
+	Migrations bool `json:"migrations" yaml:"migrations"`
+	//Modules feature modules of services, any of Modules()
+	Modules []string `json:"modules,omitempty" yaml:"modules,omitempty"`
+	//License one of Licenses(), LICENSE names the maintainer as the copyright holder
+	License string `json:"license" yaml:"license"`
+	//Year of the copyright notice, the current year when it is not given
+	Year int `json:"year,omitempty" yaml:"year,omitempty"`
+	//Contributing generates CONTRIBUTING.md
+	Contributing bool `json:"contributing" yaml:"contributing"`
+	//GoVersion go directive of go.mod, at least 1.16
+	GoVersion string `json:"go_version" yaml:"go_version"`
+	//Blueprint path to a blueprint file describing the project tree, built-in default if empty
+	Blueprint string `json:"blueprint,omitempty" yaml:"blueprint,omitempty"`
+
+	//OutputDir, ConflictPolicy and KeepInvalid describe how a run writes, they are not answers and are never saved
+	OutputDir      string `json:"-" yaml:"-"`
+	ConflictPolicy string `json:"-" yaml:"-"`
+	KeepInvalid    bool   `json:"-" yaml:"-"`
 }
 
-func getTemplate(file string) func() []byte {
-	var x func() []byte
-	var ok bool
-	if x, ok = templateMap[file]; !ok {
-		log.Panicf("Template for file %s does not exist", file)
+//Complete fills in every option that can be derived from the full name and is not already set
+func (o *Options) Complete() {
+	if o.FullName == "" {
+		return
+	}
+	o.FullName = common.SanitizeName(o.FullName)
+	nameData := common.ExtractNameData(o.FullName)
+	if o.ProjectName == "" {
+		o.ProjectName = nameData.ProjectName
+	}
+	if o.Maintainer == "" {
+		o.Maintainer = nameData.Maintainer
+	}
+	if o.Archetype == "" {
+		o.Archetype = DefaultArchetype()
+	}
+	kind := o.Kind()
+	if o.Framework == "" {
+		o.Framework = DefaultHTTPFramework()
+		if kind != nil && !kind.HTTP {
+			o.Framework = noFramework
+		}
+	}
+	if len(o.Transports) == 0 {
+		o.Transports = DefaultTransports()
+		if kind != nil && kind.Transports != nil {
+			o.Transports = append([]string{}, kind.Transports...)
+		}
+	}
+	if o.Database == "" {
+		o.Database = DefaultDatabase()
+	}
+	if o.License == "" {
+		o.License = DefaultLicense()
+	}
+	if o.Year == 0 {
+		o.Year = time.Now().Year()
+	}
+	if o.GoVersion == "" {
+		o.GoVersion = DefaultGoVersion()
+	}
+	if o.ConflictPolicy == "" {
+		o.ConflictPolicy = ConflictFail
 	}
-	return x
 }
 
-func mainTemplate() []byte {
-	if executeOptions.Framework != "" {
+//Names every name derived from the module path, available to templates as {{.Names.DockerName}}
+func (o *Options) Names() *common.NameData {
+	names := common.ExtractNameData(o.FullName)
+	names.Rename(o.ProjectName)
+	if o.Maintainer != "" {
+		names.Maintainer = o.Maintainer
+	}
+	return names
+}
 
-		switch executeOptions.Framework {
-		case ginFramework:
-			return templates.GinTemplate()
+//Validate checks that options are complete enough to build a project
+func (o *Options) Validate() error {
+	if o.FullName == "" {
+		return errors.New("full name is required (eg. github.com/AkronimBlack/project)")
+	}
+	if _, err := common.ParseModulePath(o.FullName); err != nil {
+		return err
+	}
+	if o.ProjectName == "" {
+		return fmt.Errorf("could not determine project name from %s", o.FullName)
+	}
+	kind := o.Kind()
+	if kind == nil {
+		return fmt.Errorf("unknown archetype %s, available: %s", o.Archetype, strings.Join(Archetypes(), ", "))
+	}
+	if !isHTTPFramework(o.Framework) {
+		return fmt.Errorf("unknown http framework %s, available: %s", o.Framework, strings.Join(HTTPFrameworks(), ", "))
+	}
+	if !kind.HTTP && o.Framework != noFramework {
+		return fmt.Errorf("%s projects have no http server, the http framework has to be %s", kind.Name, noFramework)
+	}
+	for _, x := range o.Transports {
+		if !isTransport(x) {
+			return fmt.Errorf("unknown transport %s, available: %s", x, strings.Join(Transports(), ", "))
 		}
 	}
-	return templates.MainTemplate()
+	switch {
+	case kind.Transports == nil && len(o.Transports) == 0:
+		return fmt.Errorf("at least one transport is required, available: %s", strings.Join(Transports(), ", "))
+	case kind.Transports != nil && len(kind.Transports) == 0 && len(o.Transports) > 0:
+		return fmt.Errorf("%s projects have no transports", kind.Name)
+	case kind.Transports != nil && !sameTransports(kind.Transports, o.Transports):
+		return fmt.Errorf("%s projects are reached through %s only", kind.Name, strings.Join(kind.Transports, ", "))
+	}
+	if !isDatabase(o.Database) {
+		return fmt.Errorf("unknown database %s, available: %s", o.Database, strings.Join(Databases(), ", "))
+	}
+	if !kind.Service && o.Database != noDatabase {
+		return fmt.Errorf("%s projects do not connect to a database, the database has to be %s", kind.Name, noDatabase)
+	}
+	if db := o.DB(); o.Migrations && (db == nil || !db.SQL()) {
+		return fmt.Errorf("migrations need a sql database (%s), not %s", strings.Join(SQLDatabases(), ", "), o.Database)
+	}
+	for _, x := range o.Modules {
+		if !isModule(x) {
+			return fmt.Errorf("unknown module %s, available: %s", x, strings.Join(Modules(), ", "))
+		}
+	}
+	if !kind.Service && len(o.Modules) > 0 {
+		return fmt.Errorf("%s projects do not take modules, only services do", kind.Name)
+	}
+	if !isLicense(o.License) {
+		return fmt.Errorf("unknown license %s, available: %s", o.License, strings.Join(Licenses(), ", "))
+	}
+	if err := validateGoVersion(o.GoVersion); err != nil {
+		return err
+	}
+	if o.ConflictPolicy != "" && !isConflictPolicy(o.ConflictPolicy) {
+		return fmt.Errorf("unknown conflict policy %s, available: %s", o.ConflictPolicy, strings.Join(ConflictPolicies(), ", "))
+	}
+	return nil
 }
 
-func goModTemplate() []byte {
-	base := []byte(`module {{.FullName}}
-
-go 1.15
-require (
-	github.com/joho/godotenv v1.3.0
-`)
-
-	if executeOptions.Framework != "" {
-
-		base = append(base, templates.GetDependency(executeOptions.Framework)...)
+func mainTemplate() ([]byte, error) {
+	if framework := FrameworkByName(executeOptions.Framework); framework != nil {
+		return templates.Load(framework.MainTemplate)
 	}
+	return templates.Load("none/main.go")
+}
 
-	return append(base, []byte(`    
-	)`)...)
+//databaseTemplate loads the template name picks from the selected database
+func databaseTemplate(name func(d *Database) string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		db := executeOptions.DB()
+		if db == nil {
+			return nil, fmt.Errorf("database %s has no templates", executeOptions.Database)
+		}
+		return templates.Load(name(db))
+	}
+}
 
+//goModTemplate go.mod written from the dependency catalog, it has no template actions
+func goModTemplate() ([]byte, error) {
+	return executeOptions.GoMod()
 }
 
 //NewConfig config constructor
@@ -125,6 +302,17 @@ type Config struct {
 	FullName     string      `json:"full_name"`
 	Maintainer   string      `json:"maintainer"`
 	TemplateData interface{} `json:"template_data"`
+	//OutputDir every object is created relative to this directory, current working directory if empty
+	OutputDir string `json:"output_dir"`
+	//ConflictPolicy what to do with files that already exist and differ from the rendered template
+	ConflictPolicy string `json:"conflict_policy"`
+	//KeepInvalid writes generated files that do not parse and only logs the problems, see Validate
+	KeepInvalid bool `json:"keep_invalid"`
+}
+
+//path location of an object on disk
+func (c *Config) path(name string) string {
+	return filepath.Join(c.OutputDir, name)
 }
 
 type Builder interface {
@@ -132,78 +320,103 @@ type Builder interface {
 }
 
 type Object struct {
-	Name       string
-	Type       string
-	SubObjects []*Object
-	Template   func() []byte
+	Name       string    `json:"name" yaml:"name"`
+	Type       string    `json:"type,omitempty" yaml:"type,omitempty"`
+	SubObjects []*Object `json:"children,omitempty" yaml:"children,omitempty"`
+	//TemplateName built-in template rendered for a file
+	TemplateName string `json:"template,omitempty" yaml:"template,omitempty"`
+	//Source template file rendered for a file, relative to the blueprint it is declared in
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	//When text/template condition on the wizard answers, eg. eq .Framework "gin"
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+	//Delims custom template delimiters, eg. ["[[", "]]"] for files that contain {{ themselves
+	Delims   []string               `json:"delims,omitempty" yaml:"delims,omitempty"`
+	Template func() ([]byte, error) `json:"-" yaml:"-"`
+	//Generated files are owned by the generator and replaced on every run, they never conflict
+	Generated bool `json:"generated,omitempty" yaml:"generated,omitempty"`
+	//Edit changes a file instead of rendering a template, eg. registering routes in main.go.
+	//It gets the current content, nil when the file does not exist yet. Edited files never conflict
+	Edit func(existing []byte) ([]byte, error) `json:"-" yaml:"-"`
+	//Data template data for the name and template of this object, config.TemplateData when nil.
+	//Lets one run generate the same template for several values, eg. a repository per table
+	Data interface{} `json:"-" yaml:"-"`
+	//Content is written as is instead of rendering a template, eg. the manifest and the merged files of stock regenerate
+	Content []byte `json:"-" yaml:"-"`
 }
 
-//Build start building
+//Build renders the object tree into a staging directory and moves it into place.
+//On any error the output directory is left exactly as it was
 func (o *Object) Build(config *Config) error {
-	o.replacePlaceholder(config)
-	log.Printf("Generating %s ", o.Name)
-	var err error
-	switch o.Type {
-	case TypeFile:
-		err = o.generateFile(config)
-	case TypeDir:
-		log.Println("Making directory", o.Name)
-		err = os.MkdirAll(o.Name, os.ModePerm)
+	return Generate(config, []*Object{o})
+}
+
+//renderName replaces NamePlaceholder and executes the object name as a template against the wizard answers,
+//eg. "internal/{{.Entity | snake}}/handler.go"
+func (o *Object) renderName(config *Config) (string, error) {
+	name := o.Name
+	if strings.Contains(name, NamePlaceholder) {
+		replacement := config.Name
+		if replacement == "" {
+			replacement = config.FullName
+		}
+		name = strings.ReplaceAll(name, NamePlaceholder, replacement)
 	}
-	if err != nil {
-		return err
+	if !strings.Contains(name, "{{") {
+		return name, nil
 	}
-	if o.SubObjects == nil {
-		return nil
+	nameTemplate, err := templates.Parse(o.Name, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("name %s: %w", o.Name, err)
 	}
-	for _, x := range o.SubObjects {
-		x.Name = fmt.Sprintf("%s/%s", o.Name, x.Name)
-		err = x.Build(config)
-		if err != nil {
-			return err
-		}
+	var buf bytes.Buffer
+	if err = nameTemplate.Execute(&buf, o.data(config)); err != nil {
+		return "", fmt.Errorf("name %s: %w", o.Name, err)
 	}
-	return nil
+	return strings.Trim(strings.TrimSpace(buf.String()), "/"), nil
 }
 
-func (o *Object) replacePlaceholder(config *Config) {
-	var name string
-	parts := strings.Split(o.Name, "/")
-	if len(parts) > 1 {
-		name = parts[len(parts)-1]
-	} else {
-		name = o.Name
-	}
-	if name == NamePlaceholder {
-		var replacement string
-		log.Println("name is placeholder. replacing with ", config.FullName)
-		if config.Name != "" {
-			replacement = config.Name
-		} else {
-			replacement = config.FullName
-		}
+//data template data of the object
+func (o *Object) data(config *Config) interface{} {
+	if o.Data != nil {
+		return o.Data
+	}
+	return config.TemplateData
+}
 
-		if len(parts) > 1 {
-			parts[len(parts)-1] = replacement
-			o.Name = strings.Join(parts, "/")
-			return
-		}
-		o.Name = replacement
+//edit applies Edit to the file at path
+func (o *Object) edit(path string) ([]byte, error) {
+	existing, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
 	}
+	return o.Edit(existing)
 }
 
-func (o *Object) generateFile(config *Config) error {
-	log.Printf("Creating: %s", o.Name)
-	f, err := os.Create(o.Name)
-	if err != nil {
-		log.Panic(err.Error())
+//render executes the object template against config without touching the disk
+func (o *Object) render(config *Config) ([]byte, error) {
+	if o.Content != nil {
+		return o.Content, nil
 	}
-	if o.Template != nil {
-		mainTemplate := template.Must(template.New("main").Parse(string(o.Template())))
-		err = mainTemplate.Execute(f, config.TemplateData)
+	if o.Template == nil {
+		return nil, nil
 	}
+	source, err := o.Template()
 	if err != nil {
-		log.Panic(err)
+		return nil, err
+	}
+	mainTemplate, err := templates.Parse(o.Name, source, o.Delims...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = mainTemplate.Execute(&buf, o.data(config)); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(o.Name, ".go") {
+		//go files are gofmt'd, invalid ones are written as rendered so the error shows up where it belongs
+		if formatted, err := format.Source(buf.Bytes()); err == nil {
+			return formatted, nil
+		}
 	}
-	return f.Close()
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,90 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderName(t *testing.T) {
+	opts := NewOptionsFromName("example.com/acme/order-service", "")
+	tests := []struct {
+		name    string
+		object  *Object
+		config  *Config
+		want    string
+		wantErr string
+	}{
+		{"plain", &Object{Name: "domain"}, nil, "domain", ""},
+		{"placeholder", &Object{Name: "{app_name}"}, nil, "order-service", ""},
+		{"placeholder without a name", &Object{Name: "{app_name}"}, &Config{FullName: "example.com/acme/x"}, "example.com/acme/x", ""},
+		{"template", &Object{Name: "cmd/{{.Names.BinaryName}}"}, nil, "cmd/order-service", ""},
+		{"functions", &Object{Name: "internal/{{.ProjectName | snake}}/doc.go"}, nil, "internal/order_service/doc.go", ""},
+		{"slashes and spaces trimmed", &Object{Name: " /{{.Maintainer}}/ "}, nil, "acme", ""},
+		{"empty", &Object{Name: `{{if .HasTransport "grpc"}}proto{{end}}`}, nil, "", ""},
+		{"own data", &Object{Name: "{{.}}.go", Data: "orders"}, nil, "orders.go", ""},
+		{"invalid template", &Object{Name: "{{.ProjectName"}, nil, "", "name {{.ProjectName"},
+		{"missing field", &Object{Name: "{{.Unknown}}"}, nil, "", "can't evaluate field Unknown"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			config := x.config
+			if config == nil {
+				config = NewConfig(opts.ProjectName, opts.FullName, opts.Maintainer, opts)
+			}
+			got, err := x.object.renderName(config)
+			if x.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, x.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != x.want {
+				t.Errorf("name = %q, want %q", got, x.want)
+			}
+		})
+	}
+}
+
+//TestTransportDirectories the rest blueprint only creates the api and transport directories of the chosen transports
+func TestTransportDirectories(t *testing.T) {
+	directories := map[string]string{
+		"api/openapi":                   transportHTTP,
+		"api/proto":                     transportGRPC,
+		"infrastructure/transport/http": transportHTTP,
+		"infrastructure/transport/grpc": transportGRPC,
+		"infrastructure/transport/amqp": transportAMQP,
+	}
+	for _, transports := range [][]string{
+		{transportHTTP},
+		{transportGRPC},
+		{transportAMQP},
+		{transportHTTP, transportAMQP},
+		{transportHTTP, transportGRPC, transportAMQP},
+	} {
+		t.Run(strings.Join(transports, ","), func(t *testing.T) {
+			opts := NewOptionsFromName("example.com/acme/shop", "")
+			opts.Transports = transports
+			entries, err := Plan(opts, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			planned := map[string]bool{}
+			var walk func(entries []*PlanEntry)
+			walk = func(entries []*PlanEntry) {
+				for _, x := range entries {
+					planned[strings.TrimPrefix(x.Path, "shop/")] = true
+					walk(x.Children)
+				}
+			}
+			walk(entries)
+			for dir, transport := range directories {
+				if want := opts.HasTransport(transport); planned[dir] != want {
+					t.Errorf("%s planned: %v, want %v", dir, planned[dir], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package wizard
+
+const (
+	ginFramework     = "gin"
+	gorillaFramework = "gorilla"
+	chiFramework     = "chi"
+	echoFramework    = "echo"
+	noFramework      = "none"
+)
+
+//Framework everything the generator needs to know about a http framework.
+//MainTemplate names the template for cmd/{app_name}/main.go with the router and middleware (CORS, logging, recovery),
+//HandlerTemplate the template for entity handlers and their route registration, RoutesTemplate the route registration
+//generated from an OpenAPI document and Module is the module that identifies the framework in an existing go.mod.
+//The modules required by go.mod are listed in the dependency catalog under framework:{name}
+type Framework struct {
+	Name            string
+	Module          string
+	MainTemplate    string
+	HandlerTemplate string
+	RoutesTemplate  string
+}
+
+//frameworks is the single registry of http frameworks, the order is the order offered by the wizard
+var frameworks = []*Framework{
+	{
+		Name:            ginFramework,
+		Module:          "github.com/gin-gonic/gin",
+		MainTemplate:    "gin/main.go",
+		HandlerTemplate: "gin/handler.go",
+		RoutesTemplate:  "gin/openapi_routes.go",
+	},
+	{
+		Name:            gorillaFramework,
+		Module:          "github.com/gorilla/mux",
+		MainTemplate:    "gorilla/main.go",
+		HandlerTemplate: "gorilla/handler.go",
+		RoutesTemplate:  "gorilla/openapi_routes.go",
+	},
+	{
+		Name:            chiFramework,
+		Module:          "github.com/go-chi/chi/v5",
+		MainTemplate:    "chi/main.go",
+		HandlerTemplate: "chi/handler.go",
+		RoutesTemplate:  "chi/openapi_routes.go",
+	},
+	{
+		Name:            echoFramework,
+		Module:          "github.com/labstack/echo/v4",
+		MainTemplate:    "echo/main.go",
+		HandlerTemplate: "echo/handler.go",
+		RoutesTemplate:  "echo/openapi_routes.go",
+	},
+	{
+		Name:            noFramework,
+		MainTemplate:    "none/main.go",
+		HandlerTemplate: "none/handler.go",
+		RoutesTemplate:  "none/openapi_routes.go",
+	},
+}
+
+//HTTPFrameworks list of available framework templates
+func HTTPFrameworks() []string {
+	names := make([]string, 0, len(frameworks))
+	for _, x := range frameworks {
+		names = append(names, x.Name)
+	}
+	return names
+}
+
+//DefaultHTTPFramework http framework used when none is given
+func DefaultHTTPFramework() string {
+	return ginFramework
+}
+
+//FrameworkByName registered framework, nil if there is none with that name
+func FrameworkByName(name string) *Framework {
+	for _, x := range frameworks {
+		if x.Name == name {
+			return x
+		}
+	}
+	return nil
+}
+
+func isHTTPFramework(name string) bool {
+	return FrameworkByName(name) != nil
+}
@@ -0,0 +1,54 @@
+package wizard
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+//TestFrameworks every registered framework has its templates and generates a main.go importing the framework only
+func TestFrameworks(t *testing.T) {
+	if FrameworkByName(DefaultHTTPFramework()) == nil {
+		t.Errorf("the default framework %s is not registered", DefaultHTTPFramework())
+	}
+	if FrameworkByName("fiber") != nil || isHTTPFramework("fiber") {
+		t.Error("fiber is not a registered framework")
+	}
+	for _, name := range HTTPFrameworks() {
+		t.Run(name, func(t *testing.T) {
+			framework := FrameworkByName(name)
+			for _, x := range []string{framework.MainTemplate, framework.HandlerTemplate, framework.RoutesTemplate} {
+				if _, err := templates.Get(x); err != nil {
+					t.Errorf("template %s: %v", x, err)
+				}
+			}
+
+			files := planFiles(t, NewOptionsFromName("example.com/acme/shop", name))
+			f, err := parser.ParseFile(token.NewFileSet(), "main.go", files["cmd/shop/main.go"], parser.ImportsOnly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			imported := map[string]bool{}
+			for _, x := range f.Imports {
+				path, _ := strconv.Unquote(x.Path.Value)
+				imported[path] = true
+			}
+			for _, other := range frameworks {
+				if other.Module == "" {
+					continue
+				}
+				want := other.Name == name
+				if imported[other.Module] != want {
+					t.Errorf("main.go imports %s: %v, want %v", other.Module, imported[other.Module], want)
+				}
+				if got := strings.Contains(files["go.mod"], other.Module+" "); got != want {
+					t.Errorf("go.mod requires %s: %v, want %v\n%s", other.Module, got, want, files["go.mod"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package wizard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/goedit"
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+const grpcServerFile = "infrastructure/transport/grpc/server.go"
+
+//GRPCService a service definition in api/proto and its server in infrastructure/transport/grpc,
+//templates reach it as {{.GRPCService.Name}}
+type GRPCService struct {
+	//Name service name without the Service suffix, eg. Order for OrderService
+	Name string `json:"name"`
+	//Package proto package without the version, eg. order for order.v1
+	Package string `json:"package"`
+	Version string `json:"version"`
+	Module  string `json:"module"`
+}
+
+//NewGRPCService service name in module, a trailing Service is dropped (order_service => Order)
+func NewGRPCService(name, module string) (*GRPCService, error) {
+	name = templates.GoName(name)
+	if trimmed := strings.TrimSuffix(name, "Service"); trimmed != "" {
+		name = trimmed
+	}
+	if name == "" || name[0] < 'A' || name[0] > 'Z' {
+		return nil, fmt.Errorf("invalid grpc service name %q", name)
+	}
+	return &GRPCService{
+		Name:    name,
+		Package: strings.ReplaceAll(templates.Snake(name), "_", ""),
+		Version: "v1",
+		Module:  module,
+	}, nil
+}
+
+//FileName base name of the .proto and server files
+func (s *GRPCService) FileName() string {
+	return templates.Snake(s.Name)
+}
+
+//ProtoFile location of the service definition relative to the project root
+func (s *GRPCService) ProtoFile() string {
+	return path.Join("api/proto", s.Package, s.Version, s.FileName()+".proto")
+}
+
+//GoPackage import path of the go code generated from the .proto file
+func (s *GRPCService) GoPackage() string {
+	return path.Join(s.Module, "api/proto", s.Package, s.Version)
+}
+
+//GoAlias package name of the generated go code, eg. orderv1
+func (s *GRPCService) GoAlias() string {
+	return s.Package + s.Version
+}
+
+//GRPCService the service generated for the project when the grpc transport is selected, named after the project
+func (o *Options) GRPCService() *GRPCService {
+	service, err := NewGRPCService(o.Names().ProjectName, o.FullName)
+	if err != nil {
+		return &GRPCService{Name: "App", Package: "app", Version: "v1", Module: o.FullName}
+	}
+	return service
+}
+
+//grpcServiceData template data for add:grpc-service, the same shape templates see through Options.GRPCService
+type grpcServiceData struct {
+	GRPCService *GRPCService
+}
+
+//NewGRPCServiceConfig generator config writing the service files into the project directory
+func NewGRPCServiceConfig(project *Project, service *GRPCService, conflictPolicy string) *Config {
+	config := NewConfig(service.Name, project.ModulePath, project.Names.Maintainer, &grpcServiceData{GRPCService: service})
+	config.OutputDir = project.Dir
+	config.ConflictPolicy = conflictPolicy
+	return config
+}
+
+//GRPCServiceObjects files generated for a service added to project, the shared server.go only when it is missing
+func GRPCServiceObjects(project *Project) []*Object {
+	objects := []*Object{
+		{
+			Name:     "api/proto/{{.GRPCService.Package}}/{{.GRPCService.Version}}/{{.GRPCService.FileName}}.proto",
+			Type:     TypeFile,
+			Template: templates.Loader("grpc/service.proto"),
+		},
+		{
+			Name:     "infrastructure/transport/grpc/{{.GRPCService.FileName}}_server.go",
+			Type:     TypeFile,
+			Template: templates.Loader("grpc/service_server.go"),
+		},
+	}
+	if _, err := os.Stat(filepath.Join(project.Dir, grpcServerFile)); os.IsNotExist(err) {
+		objects = append(objects, &Object{
+			Name:     grpcServerFile,
+			Type:     TypeFile,
+			Template: templates.Loader("grpc/server.go"),
+		})
+	}
+	return objects
+}
+
+//RegisterObject edits mainFile so grpcServer() registers the service
+func (s *GRPCService) RegisterObject(mainFile string) *Object {
+	return &Object{
+		Name: mainFile,
+		Type: TypeFile,
+		Edit: s.register,
+	}
+}
+
+func (s *GRPCService) register(src []byte) ([]byte, error) {
+	file, err := goedit.Parse("main.go", src)
+	if err != nil {
+		return nil, err
+	}
+	if err = file.AddImport(s.GoAlias(), s.GoPackage()); err != nil {
+		return nil, err
+	}
+	err = file.AddStatement("grpcServer", fmt.Sprintf("%s.Register%sServiceServer(server, grpctransport.New%sServer())", s.GoAlias(), s.Name, s.Name))
+	if errors.Is(err, goedit.ErrNotFound) {
+		return nil, fmt.Errorf("%w, scaffold the project with the grpc transport or use --no-register", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file.Bytes()
+}
@@ -0,0 +1,74 @@
+package wizard
+
+import (
+	"fmt"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+const (
+	apacheLicense      = "Apache-2.0"
+	mitLicense         = "MIT"
+	bsdLicense         = "BSD-3-Clause"
+	mplLicense         = "MPL-2.0"
+	proprietaryLicense = "proprietary"
+)
+
+//License a license LICENSE can be generated with. Name is the SPDX identifier (proprietary has none) and Template
+//names the template of the license text, rendered with the maintainer as the copyright holder and Options.Year.
+//The MPL text is kept verbatim, its notice belongs in the source files
+type License struct {
+	Name     string
+	Template string
+}
+
+//licenses is the single registry of licenses, the order is the order offered by the wizard
+var licenses = []*License{
+	{Name: apacheLicense, Template: "licenses/apache-2.0"},
+	{Name: mitLicense, Template: "licenses/mit"},
+	{Name: bsdLicense, Template: "licenses/bsd-3-clause"},
+	{Name: mplLicense, Template: "licenses/mpl-2.0"},
+	{Name: proprietaryLicense, Template: "licenses/proprietary"},
+}
+
+//Licenses list of licenses a project can be generated with
+func Licenses() []string {
+	names := make([]string, 0, len(licenses))
+	for _, x := range licenses {
+		names = append(names, x.Name)
+	}
+	return names
+}
+
+//DefaultLicense license used when none is given, nothing is open sourced by accident
+func DefaultLicense() string {
+	return proprietaryLicense
+}
+
+//LicenseByName registered license, nil if there is none with that name
+func LicenseByName(name string) *License {
+	for _, x := range licenses {
+		if x.Name == name {
+			return x
+		}
+	}
+	return nil
+}
+
+func isLicense(name string) bool {
+	return LicenseByName(name) != nil
+}
+
+//OpenSource reports if the selected license is an open source license, used by templates
+//eg. {{if .OpenSource}}
+func (o *Options) OpenSource() bool {
+	return isLicense(o.License) && o.License != proprietaryLicense
+}
+
+func licenseTemplate() ([]byte, error) {
+	license := LicenseByName(executeOptions.License)
+	if license == nil {
+		return nil, fmt.Errorf("license %s has no template", executeOptions.License)
+	}
+	return templates.Load(license.Template)
+}
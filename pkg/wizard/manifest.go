@@ -0,0 +1,268 @@
+package wizard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/AkronimBlack/stock/pkg/diff"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	//ManifestName manifest written to the root of generated projects, stock regenerate reads it
+	ManifestName = ".stock.yaml"
+	//ManifestDir copies of the generated files, the base stock regenerate merges the new templates and your changes against
+	ManifestDir = ".stock"
+)
+
+const (
+	//RegenerateCreated file is new in the templates and did not exist
+	RegenerateCreated = "created"
+	//RegenerateUnchanged file is the regenerated one or the templates did not change the lines around your changes
+	RegenerateUnchanged = "unchanged"
+	//RegenerateUpdated file was not changed since it was generated and is replaced
+	RegenerateUpdated = "updated"
+	//RegenerateMerged file was changed and the template changes merged into it
+	RegenerateMerged = "merged"
+	//RegenerateConflict file and template changed the same lines, both are kept between conflict markers
+	RegenerateConflict = "conflict"
+	//RegenerateDeleted file was deleted since it was generated and is not created again
+	RegenerateDeleted = "deleted"
+	//RegenerateRemoved file is no longer generated, it is left as is
+	RegenerateRemoved = "removed"
+)
+
+//Manifest records how a project was generated so it can be regenerated when the templates change
+type Manifest struct {
+	Options          *Options `json:"options" yaml:"options"`
+	Blueprint        string   `json:"blueprint" yaml:"blueprint"`
+	BlueprintVersion string   `json:"blueprint_version" yaml:"blueprint_version"`
+	//Root project directory relative to the output directory, the single top level directory of the blueprint
+	Root  string          `json:"root,omitempty" yaml:"root,omitempty"`
+	Files []*ManifestFile `json:"files" yaml:"files"`
+}
+
+//ManifestFile a generated file relative to the project root and the sha256 of its generated content
+type ManifestFile struct {
+	Path string `json:"path" yaml:"path"`
+	Hash string `json:"hash" yaml:"hash"`
+}
+
+//RegeneratedFile what stock regenerate does with a file, Status is one of the Regenerate constants
+type RegeneratedFile struct {
+	Path      string `json:"path"`
+	Status    string `json:"status"`
+	Conflicts int    `json:"conflicts,omitempty"`
+}
+
+//renderedFile a rendered file relative to the project root
+type renderedFile struct {
+	path    string
+	content []byte
+}
+
+//LoadManifest reads the manifest of the project in dir
+func LoadManifest(dir string) (*Manifest, error) {
+	name := filepath.Join(dir, ManifestName)
+	b, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s does not exist, only projects generated by make:app or wiz can be regenerated", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err = yaml.Unmarshal(b, manifest); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if manifest.Options == nil {
+		return nil, fmt.Errorf("%s has no options", name)
+	}
+	return manifest, nil
+}
+
+//projectObjects object tree of the blueprint for opts followed by the manifest and the copies of the generated files
+func projectObjects(config *Config, opts *Options) ([]*Object, error) {
+	blueprint, err := loadBlueprint(opts)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := PlanObjects(config, blueprint.Objects, true)
+	if err != nil {
+		return nil, err
+	}
+	root := projectRoot(blueprint.Objects, entries)
+	manifest := &Manifest{Options: opts, Blueprint: blueprint.Name, BlueprintVersion: blueprint.Version, Root: root}
+	objects, err := manifest.objects(renderedFiles(entries, root))
+	if err != nil {
+		return nil, err
+	}
+	if root != "" {
+		//inside the root directory, it is moved into place with everything in it
+		blueprint.Objects[0].SubObjects = append(blueprint.Objects[0].SubObjects, objects...)
+		return blueprint.Objects, nil
+	}
+	return append(blueprint.Objects, objects...), nil
+}
+
+//objects the manifest recording files and the copies of files, relative to the project root
+func (m *Manifest) objects(files []*renderedFile) ([]*Object, error) {
+	m.Files = make([]*ManifestFile, 0, len(files))
+	objects := make([]*Object, 0, len(files)+1)
+	for _, x := range files {
+		m.Files = append(m.Files, &ManifestFile{Path: x.path, Hash: hash(x.content)})
+		objects = append(objects, &Object{
+			Name:      path.Join(ManifestDir, x.path),
+			Type:      TypeFile,
+			Generated: true,
+			Content:   x.content,
+		})
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Object{Name: ManifestName, Type: TypeFile, Generated: true, Content: b}
+	return append([]*Object{manifest}, objects...), nil
+}
+
+//Regenerate renders the project in dir again with the answers of its manifest and three-way merges every file:
+//the copy made when it was generated is the base, the file on disk and the regenerated file are the two sides.
+//It returns the config and objects that write the result and the manifest and what happens to every file
+func Regenerate(dir string) (*Config, []*Object, []*RegeneratedFile, error) {
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	opts := manifest.Options
+	opts.Complete()
+	config, err := prepare(opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blueprint, err := loadBlueprint(opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entries, err := PlanObjects(config, blueprint.Objects, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	files := renderedFiles(entries, manifest.Root)
+
+	recorded := map[string]string{}
+	for _, x := range manifest.Files {
+		recorded[x.Path] = x.Hash
+	}
+	var objects []*Object
+	var results []*RegeneratedFile
+	for _, x := range files {
+		result, content, err := regenerateFile(dir, x, recorded)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		results = append(results, result)
+		delete(recorded, x.path)
+		//files with conflict markers do not parse until the developer resolves them
+		if result.Status == RegenerateConflict {
+			config.KeepInvalid = true
+		}
+		if content != nil {
+			objects = append(objects, &Object{Name: x.path, Type: TypeFile, Generated: true, Content: content})
+		}
+	}
+	for _, x := range manifest.Files {
+		if _, ok := recorded[x.Path]; ok {
+			results = append(results, &RegeneratedFile{Path: x.Path, Status: RegenerateRemoved})
+		}
+	}
+
+	regenerated := &Manifest{Options: opts, Blueprint: blueprint.Name, BlueprintVersion: blueprint.Version, Root: manifest.Root}
+	manifestObjects, err := regenerated.objects(files)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	config.OutputDir = dir
+	config.ConflictPolicy = ConflictFail
+	return config, append(objects, manifestObjects...), results, nil
+}
+
+//regenerateFile merges the regenerated file x with the file on disk, content is nil when the file is left as is
+func regenerateFile(dir string, x *renderedFile, recorded map[string]string) (*RegeneratedFile, []byte, error) {
+	result := &RegeneratedFile{Path: x.path}
+	current, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(x.path)))
+	if os.IsNotExist(err) {
+		if _, ok := recorded[x.path]; ok {
+			result.Status = RegenerateDeleted
+			return result, nil, nil
+		}
+		result.Status = RegenerateCreated
+		return result, x.content, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(current) == string(x.content) {
+		result.Status = RegenerateUnchanged
+		return result, nil, nil
+	}
+	if hash(current) == recorded[x.path] {
+		result.Status = RegenerateUpdated
+		return result, x.content, nil
+	}
+	//without a copy of the generated file every line either side changed conflicts
+	base, err := ioutil.ReadFile(filepath.Join(dir, ManifestDir, filepath.FromSlash(x.path)))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	merged, conflicts := diff.Merge(base, current, x.content, "current", "regenerated")
+	if string(merged) == string(current) {
+		result.Status = RegenerateUnchanged
+		return result, nil, nil
+	}
+	result.Status, result.Conflicts = RegenerateMerged, conflicts
+	if conflicts > 0 {
+		result.Status = RegenerateConflict
+	}
+	return result, merged, nil
+}
+
+//projectRoot the directory of the plan when the blueprint has a single top level directory, empty otherwise
+func projectRoot(objects []*Object, entries []*PlanEntry) string {
+	if len(objects) == 1 && len(entries) == 1 && entries[0].Type == TypeDir {
+		return entries[0].Path
+	}
+	return ""
+}
+
+//renderedFiles every file of the plan below root, with the path relative to root
+func renderedFiles(entries []*PlanEntry, root string) []*renderedFile {
+	var files []*renderedFile
+	for _, x := range entries {
+		if x.Type == TypeFile {
+			rel := x.Path
+			if root != "" {
+				rel = strings.TrimPrefix(rel, root+"/")
+			}
+			files = append(files, &renderedFile{path: rel, content: []byte(x.Content)})
+		}
+		files = append(files, renderedFiles(x.Children, root)...)
+	}
+	return files
+}
+
+//inManifestDir reports if p is one of the copies kept in ManifestDir
+func inManifestDir(p string) bool {
+	return strings.HasPrefix(p, ManifestDir+"/") || strings.Contains(p, "/"+ManifestDir+"/")
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
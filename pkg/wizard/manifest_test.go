@@ -0,0 +1,127 @@
+package wizard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func readFile(t *testing.T, name string) string {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func writeFile(t *testing.T, name, content string) {
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//regenerate runs Regenerate on dir and writes the result, it returns the status of every file
+func regenerate(t *testing.T, dir string) map[string]*RegeneratedFile {
+	config, objects, files, err := Regenerate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = Generate(config, objects); err != nil {
+		t.Fatal(err)
+	}
+	results := map[string]*RegeneratedFile{}
+	for _, x := range files {
+		results[x.Path] = x
+	}
+	return results
+}
+
+//TestRegenerate generates a project, changes it the way a developer would, adds a module to the recorded answers
+//so the templates render differently and regenerates it
+func TestRegenerate(t *testing.T) {
+	opts := NewOptionsFromName("example.com/acme/shop", "gin")
+	opts.OutputDir = t.TempDir()
+	if err := Execute(opts); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(opts.OutputDir, "shop")
+	mainFile := filepath.Join(dir, "cmd", "shop", "main.go")
+	readme := filepath.Join(dir, "README.md")
+
+	//metrics.Start() is added right below buildDependencies(), where the developer added a line as well
+	main := readFile(t, mainFile)
+	if !strings.Contains(main, "\tbuildDependencies()\n") {
+		t.Fatalf("main.go does not call buildDependencies():\n%s", main)
+	}
+	writeFile(t, mainFile, strings.Replace(main, "\tbuildDependencies()\n", "\tbuildDependencies()\n\tlog.Println(\"starting\")\n", 1))
+	notes := "\n## Notes\n\nDeployed by the platform team.\n"
+	writeFile(t, readme, readFile(t, readme)+notes)
+	if err := os.Remove(filepath.Join(dir, ".gitignore")); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest.Options.Modules = []string{metricsModule}
+	b, err := yaml.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, ManifestName), string(b))
+
+	results := regenerate(t, dir)
+	wantStatus := map[string]string{
+		"cmd/shop/main.go":                  RegenerateConflict,
+		"README.md":                         RegenerateMerged,
+		".env":                              RegenerateUpdated,
+		".gitignore":                        RegenerateDeleted,
+		"Makefile":                          RegenerateUnchanged,
+		"infrastructure/metrics/metrics.go": RegenerateCreated,
+	}
+	for name, want := range wantStatus {
+		if x := results[name]; x == nil || x.Status != want {
+			t.Errorf("%s: got %+v, want status %s", name, x, want)
+		}
+	}
+	if x := results["cmd/shop/main.go"]; x != nil && x.Conflicts != 1 {
+		t.Errorf("main.go has %d conflicts, want 1", x.Conflicts)
+	}
+
+	main = readFile(t, mainFile)
+	conflict := "<<<<<<< current\n\tlog.Println(\"starting\")\n=======\n\tmetrics.Start()\n>>>>>>> regenerated\n"
+	if !strings.Contains(main, conflict) {
+		t.Errorf("main.go does not have the conflict\n%s\ngot\n%s", conflict, main)
+	}
+	if !strings.Contains(main, `"example.com/acme/shop/infrastructure/metrics"`) {
+		t.Errorf("the metrics import was not merged into main.go\n%s", main)
+	}
+	merged := readFile(t, readme)
+	if !strings.HasSuffix(merged, notes) || !strings.Contains(merged, "## Modules") {
+		t.Errorf("README.md does not have both the notes and the modules\n%s", merged)
+	}
+	if !strings.Contains(readFile(t, filepath.Join(dir, ".env")), "METRICS_ADDR=") {
+		t.Error(".env was not updated")
+	}
+	if _, err = os.Stat(filepath.Join(dir, ".gitignore")); !os.IsNotExist(err) {
+		t.Error(".gitignore was created again")
+	}
+	if got := readFile(t, filepath.Join(dir, ManifestDir, "cmd", "shop", "main.go")); strings.Contains(got, "<<<<<<<") || !strings.Contains(got, "metrics.Start()") {
+		t.Errorf("the copy of main.go is not the regenerated file\n%s", got)
+	}
+
+	//the regenerated files are the base now, regenerating again leaves the project as it is
+	for name, x := range regenerate(t, dir) {
+		if x.Status != RegenerateUnchanged && x.Status != RegenerateDeleted {
+			t.Errorf("%s is %s after regenerating twice", name, x.Status)
+		}
+	}
+	if got := readFile(t, mainFile); got != main {
+		t.Errorf("main.go changed when regenerating twice\n%s", got)
+	}
+}
@@ -0,0 +1,29 @@
+package wizard
+
+import (
+	"errors"
+	"path"
+	"time"
+
+	"github.com/AkronimBlack/stock/pkg/templates"
+)
+
+//MigrationsDir directory holding the migrations and their runner, relative to the project
+const MigrationsDir = "migrations"
+
+//migrationVersion layout of migration versions, UTC timestamps sort in the order migrations were created
+const migrationVersion = "20060102150405"
+
+//MigrationObjects the up and down files of a new migration in dir, {version}_{name}.up.sql and .down.sql
+//with the version taken from now
+func MigrationObjects(dir, name string, now time.Time) ([]*Object, error) {
+	name = templates.Snake(name)
+	if name == "" {
+		return nil, errors.New("migration name is required (eg. create_orders)")
+	}
+	base := path.Join(dir, now.UTC().Format(migrationVersion)+"_"+name)
+	return []*Object{
+		{Name: base + ".up.sql", Type: TypeFile, Template: templates.Loader("migrations/up.sql")},
+		{Name: base + ".down.sql", Type: TypeFile, Template: templates.Loader("migrations/down.sql")},
+	}, nil
+}
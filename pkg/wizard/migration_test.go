@@ -0,0 +1,66 @@
+package wizard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMigrationObjects(t *testing.T) {
+	now := time.Date(2024, 3, 9, 23, 5, 7, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name      string
+		dir       string
+		migration string
+		want      string
+	}{
+		{"snake name", "migrations", "create_orders", "migrations/20240309220507_create_orders"},
+		{"spaces", "migrations", "add order status", "migrations/20240309220507_add_order_status"},
+		{"camel case", "migrations", "AddOrderStatus", "migrations/20240309220507_add_order_status"},
+		{"nested dir", "db/migrations", "create_orders", "db/migrations/20240309220507_create_orders"},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			objects, err := MigrationObjects(x.dir, x.migration, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(objects) != 2 {
+				t.Fatalf("got %d objects, want the up and down files", len(objects))
+			}
+			if got := objects[0].Name; got != x.want+".up.sql" {
+				t.Errorf("up file = %s, want %s.up.sql", got, x.want)
+			}
+			if got := objects[1].Name; got != x.want+".down.sql" {
+				t.Errorf("down file = %s, want %s.down.sql", got, x.want)
+			}
+			for _, y := range objects {
+				if y.Type != TypeFile || y.Template == nil {
+					t.Errorf("%s is not a file rendered from a template", y.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationObjectsVersionOrder(t *testing.T) {
+	first, err := MigrationObjects(MigrationsDir, "first", time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := MigrationObjects(MigrationsDir, "second", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the runner sorts by the numeric version, a later migration has to get a bigger one
+	if first[0].Name >= second[0].Name {
+		t.Errorf("%s does not sort before %s", first[0].Name, second[0].Name)
+	}
+}
+
+func TestMigrationObjectsName(t *testing.T) {
+	for _, name := range []string{"", "  ", "-"} {
+		if _, err := MigrationObjects(MigrationsDir, name, time.Now()); err == nil {
+			t.Errorf("no error for migration name %q", name)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package wizard
+
+import (
+	"path"
+	"sort"
+)
+
+const (
+	metricsModule = "metrics"
+	cacheModule   = "cache"
+	authModule    = "auth"
+)
+
+//Module a feature module, something a service needs that touches several files at once.
+//Besides its own Files a module contributes to the files every service shares: Packages, Vars, Build and Start wire it
+//into main.go, Env adds keys to .env, Ports and Services add to docker-compose.yml and the dependency catalog lists
+//its requires under module:{name}. Contributions of the selected modules are merged in registry order, see Options.Contributions
+type Module struct {
+	Name        string
+	Description string
+	//Files created below the project root, Template names a built-in template
+	Files []*ModuleFile
+	//Packages of the project main.go imports, relative to the module path
+	Packages []string
+	//Vars declared in the var block of main.go, eg. "cacheClient *cache.Client"
+	Vars []string
+	//Build statements appended to buildDependencies
+	Build []string
+	//Start statements run by main once the dependencies are built, before the service blocks
+	Start []string
+	Env   []*EnvVar
+	//Ports published by the service container, eg. "9100:9100"
+	Ports []string
+	//Services run next to the service container by docker-compose
+	Services []*ComposeService
+}
+
+//ModuleFile a file of a module, Path is relative to the project root
+type ModuleFile struct {
+	Path     string
+	Template string
+}
+
+//ComposeService a docker-compose service named {docker_name}_{name}. The service container gets its host through
+//HostEnv and depends on it, Volume is where the data volume is mounted, empty when the service keeps no data
+type ComposeService struct {
+	Name        string
+	Image       string
+	Ports       []string
+	Environment []*EnvVar
+	Volume      string
+	HostEnv     string
+}
+
+//modules is the single registry of feature modules, the order is the order offered by the wizard and the order
+//contributions are merged in
+var modules = []*Module{
+	{
+		Name:        metricsModule,
+		Description: "Prometheus metrics served on a port of their own",
+		Files: []*ModuleFile{
+			{Path: "infrastructure/metrics/metrics.go", Template: "modules/metrics.go"},
+		},
+		Packages: []string{"infrastructure/metrics"},
+		Start:    []string{"metrics.Start()"},
+		Env:      []*EnvVar{{Key: "METRICS_ADDR", Value: ":9100", Description: "address metrics are served on"}},
+		Ports:    []string{"9100:9100"},
+	},
+	{
+		Name:        cacheModule,
+		Description: "Redis client with a redis container",
+		Files: []*ModuleFile{
+			{Path: "infrastructure/cache/cache.go", Template: "modules/cache.go"},
+		},
+		Packages: []string{"infrastructure/cache"},
+		Vars:     []string{"cacheClient *cache.Client"},
+		Build:    []string{"cacheClient = cache.NewClient(cache.ConfigFromEnv())"},
+		Env: []*EnvVar{
+			{Key: "REDIS_HOST", Value: "127.0.0.1", Description: "redis host"},
+			{Key: "REDIS_PORT", Value: "6379", Description: "redis port"},
+			{Key: "REDIS_PASSWORD", Value: "", Description: "redis password, empty when there is none"},
+		},
+		Services: []*ComposeService{
+			{
+				Name:    "redis",
+				Image:   "redis:7-alpine",
+				Ports:   []string{"6379:6379"},
+				Volume:  "/data",
+				HostEnv: "REDIS_HOST",
+			},
+		},
+	},
+	{
+		Name:        authModule,
+		Description: "JWT issuing and verification",
+		Files: []*ModuleFile{
+			{Path: "infrastructure/auth/auth.go", Template: "modules/auth.go"},
+			{Path: "infrastructure/auth/auth_test.go", Template: "modules/auth_test.go"},
+		},
+		Packages: []string{"infrastructure/auth"},
+		Vars:     []string{"authenticator *auth.Authenticator"},
+		Build:    []string{"authenticator = auth.NewAuthenticator(auth.ConfigFromEnv())"},
+		Env: []*EnvVar{
+			{Key: "JWT_SECRET", Value: "change-me", Description: "secret tokens are signed with"},
+			{Key: "JWT_TTL", Value: "1h", Description: "how long issued tokens are valid"},
+		},
+	},
+}
+
+//Modules list of feature modules a service can be generated with
+func Modules() []string {
+	names := make([]string, 0, len(modules))
+	for _, x := range modules {
+		names = append(names, x.Name)
+	}
+	return names
+}
+
+//ModuleByName registered feature module, nil if there is none with that name
+func ModuleByName(name string) *Module {
+	for _, x := range modules {
+		if x.Name == name {
+			return x
+		}
+	}
+	return nil
+}
+
+func isModule(name string) bool {
+	return ModuleByName(name) != nil
+}
+
+//HasModule reports if the feature module was selected, used by templates and blueprint conditions
+//eg. when: .HasModule "metrics"
+func (o *Options) HasModule(name string) bool {
+	for _, x := range o.Modules {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
+
+//SelectedModules selected feature modules in registry order, whatever order they were given in
+func (o *Options) SelectedModules() []*Module {
+	var selected []*Module
+	for _, x := range modules {
+		if o.HasModule(x.Name) {
+			selected = append(selected, x)
+		}
+	}
+	return selected
+}
+
+//Contributions what the selected feature modules add to the shared files
+type Contributions struct {
+	//Imports import paths of the packages main.go imports, sorted
+	Imports  []string
+	Vars     []string
+	Build    []string
+	Start    []string
+	Env      []*EnvVar
+	Ports    []string
+	Services []*ComposeService
+}
+
+//Contributions merges the contributions of the selected feature modules, used by templates
+//eg. {{range .Contributions.Env}}{{.Key}}={{.Value}}{{end}}.
+//Lists keep registry order, a key of .env or a port two modules contribute is taken once, from the first module
+func (o *Options) Contributions() *Contributions {
+	c := &Contributions{}
+	seen := map[string]bool{}
+	once := func(kind, value string) bool {
+		if seen[kind+":"+value] {
+			return false
+		}
+		seen[kind+":"+value] = true
+		return true
+	}
+	for _, x := range o.SelectedModules() {
+		for _, y := range x.Packages {
+			if p := path.Join(o.FullName, y); once("import", p) {
+				c.Imports = append(c.Imports, p)
+			}
+		}
+		c.Vars = append(c.Vars, x.Vars...)
+		c.Build = append(c.Build, x.Build...)
+		c.Start = append(c.Start, x.Start...)
+		for _, y := range x.Env {
+			if once("env", y.Key) {
+				c.Env = append(c.Env, y)
+			}
+		}
+		for _, y := range x.Ports {
+			if once("port", y) {
+				c.Ports = append(c.Ports, y)
+			}
+		}
+		for _, y := range x.Services {
+			if once("service", y.Name) {
+				c.Services = append(c.Services, y)
+			}
+		}
+	}
+	sort.Strings(c.Imports)
+	return c
+}
+
+//Volumes names of the services that keep their data in a volume
+func (c *Contributions) Volumes() []string {
+	var names []string
+	for _, x := range c.Services {
+		if x.Volume != "" {
+			names = append(names, x.Name)
+		}
+	}
+	return names
+}
+
+//moduleObjects files of the selected feature modules
+func moduleObjects(opts *Options) ([]*Object, error) {
+	var objects []*Object
+	for _, x := range opts.SelectedModules() {
+		for _, y := range x.Files {
+			object := &Object{Name: y.Path, Type: TypeFile, TemplateName: y.Template}
+			if err := object.resolve(""); err != nil {
+				return nil, err
+			}
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
@@ -0,0 +1,113 @@
+package wizard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//useModules replaces the feature module registry for the test
+func useModules(t *testing.T, registry ...*Module) {
+	saved := modules
+	t.Cleanup(func() { modules = saved })
+	modules = registry
+}
+
+func envKeys(env []*EnvVar) []string {
+	var keys []string
+	for _, x := range env {
+		keys = append(keys, x.Key+"="+x.Value)
+	}
+	return keys
+}
+
+func serviceNames(services []*ComposeService) []string {
+	var names []string
+	for _, x := range services {
+		names = append(names, x.Name+" "+x.Image)
+	}
+	return names
+}
+
+//TestContributions contributions are merged in registry order whatever order the modules are selected in,
+//what two modules contribute is taken once from the first one
+func TestContributions(t *testing.T) {
+	useModules(t,
+		&Module{
+			Name:     "first",
+			Packages: []string{"infrastructure/shared", "infrastructure/first"},
+			Start:    []string{"first.Start()"},
+			Env:      []*EnvVar{{Key: "SHARED", Value: "first"}, {Key: "FIRST", Value: "1"}},
+			Ports:    []string{"9000:9000"},
+			Services: []*ComposeService{{Name: "store", Image: "store:1", Volume: "/data", HostEnv: "STORE_HOST"}},
+		},
+		&Module{
+			Name:     "second",
+			Packages: []string{"infrastructure/shared", "infrastructure/second"},
+			Start:    []string{"second.Start()"},
+			Env:      []*EnvVar{{Key: "SECOND", Value: "2"}, {Key: "SHARED", Value: "second"}, {Key: "PORT", Value: "1"}},
+			Ports:    []string{"9001:9001", "9000:9000"},
+			Services: []*ComposeService{
+				{Name: "queue", Image: "queue:1", HostEnv: "QUEUE_HOST"},
+				{Name: "store", Image: "store:2", Volume: "/var/lib/store", HostEnv: "STORE_HOST"},
+			},
+		},
+	)
+	want := &Contributions{
+		Imports: []string{
+			"example.com/acme/shop/infrastructure/first",
+			"example.com/acme/shop/infrastructure/second",
+			"example.com/acme/shop/infrastructure/shared",
+		},
+		Start:    []string{"first.Start()", "second.Start()"},
+		Env:      []*EnvVar{{Key: "SHARED", Value: "first"}, {Key: "FIRST", Value: "1"}, {Key: "SECOND", Value: "2"}, {Key: "PORT", Value: "1"}},
+		Ports:    []string{"9000:9000", "9001:9001"},
+		Services: []*ComposeService{modules[0].Services[0], modules[1].Services[0]},
+	}
+
+	var compose, env string
+	for _, selected := range [][]string{{"first", "second"}, {"second", "first"}, {"second", "first", "second"}} {
+		t.Run(strings.Join(selected, ","), func(t *testing.T) {
+			opts := NewOptionsFromName("example.com/acme/shop", "")
+			opts.Modules = selected
+			got := opts.Contributions()
+			if !reflect.DeepEqual(got.Imports, want.Imports) || !reflect.DeepEqual(got.Start, want.Start) ||
+				!reflect.DeepEqual(got.Ports, want.Ports) {
+				t.Errorf("imports %v, start %v, ports %v\nwant %v, %v, %v", got.Imports, got.Start, got.Ports, want.Imports, want.Start, want.Ports)
+			}
+			if keys := envKeys(got.Env); !reflect.DeepEqual(keys, envKeys(want.Env)) {
+				t.Errorf("env %v, want %v", keys, envKeys(want.Env))
+			}
+			if names := serviceNames(got.Services); !reflect.DeepEqual(names, serviceNames(want.Services)) {
+				t.Errorf("services %v, want %v", names, serviceNames(want.Services))
+			}
+			if volumes := got.Volumes(); !reflect.DeepEqual(volumes, []string{"store"}) {
+				t.Errorf("volumes %v, want [store]", volumes)
+			}
+
+			files := planFiles(t, opts)
+			//the project's own PORT is kept, every key is written once
+			if keys := strings.Count(files[".env"], "\nPORT="); keys != 1 || !strings.Contains(files[".env"], "\nPORT=8080\n") {
+				t.Errorf(".env has PORT %d times, want once with the project port\n%s", keys, files[".env"])
+			}
+			for _, x := range []string{"SHARED=first\n", "FIRST=1\n", "SECOND=2\n"} {
+				if n := strings.Count(files[".env"], x); n != 1 {
+					t.Errorf(".env has %q %d times, want once\n%s", x, n, files[".env"])
+				}
+			}
+			for _, x := range []string{"shop_store:\n", "shop_queue:\n", "- 9000:9000\n", "- 9001:9001\n", "shop_store_data: {}\n", "- shop_store\n", "STORE_HOST: shop_store\n"} {
+				if n := strings.Count(files["docker-compose.yml"], x); n != 1 {
+					t.Errorf("docker-compose.yml has %q %d times, want once\n%s", x, n, files["docker-compose.yml"])
+				}
+			}
+			if strings.Contains(files["docker-compose.yml"], "store:2") {
+				t.Errorf("docker-compose.yml has the store service of the second module\n%s", files["docker-compose.yml"])
+			}
+			if compose == "" {
+				compose, env = files["docker-compose.yml"], files[".env"]
+			} else if files["docker-compose.yml"] != compose || files[".env"] != env {
+				t.Errorf("the selection order changed docker-compose.yml or .env\n%s\n%s", files["docker-compose.yml"], files[".env"])
+			}
+		})
+	}
+}
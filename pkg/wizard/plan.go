@@ -0,0 +1,202 @@
+package wizard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+const (
+	PlanFormatTree = "tree"
+	PlanFormatJSON = "json"
+
+	//PlanStatusNew file does not exist yet
+	PlanStatusNew = "new"
+	//PlanStatusUnchanged file exists with the same content
+	PlanStatusUnchanged = "unchanged"
+	//PlanStatusConflict file exists with different content, the conflict policy decides what happens
+	PlanStatusConflict = "conflict"
+	//PlanStatusModified file exists and is changed by an edit or regenerated, see Object.Edit and Object.Generated
+	PlanStatusModified = "modified"
+)
+
+//PlanEntry a directory or file the generator would create, Content is only kept when a preview is requested
+type PlanEntry struct {
+	Path     string       `json:"path"`
+	Type     string       `json:"type"`
+	Size     int          `json:"size,omitempty"`
+	Status   string       `json:"status,omitempty"`
+	Content  string       `json:"content,omitempty"`
+	Children []*PlanEntry `json:"children,omitempty"`
+}
+
+//Plan renders every template of the object map and returns the tree that Execute would create.
+//Nothing is written to disk
+func Plan(opts *Options, preview bool) ([]*PlanEntry, error) {
+	config, err := prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := projectObjects(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	return PlanObjects(config, objects, preview)
+}
+
+//PlanObjects renders objects against config, see Object.Plan
+func PlanObjects(config *Config, objects []*Object, preview bool) ([]*PlanEntry, error) {
+	var entries []*PlanEntry
+	for _, x := range objects {
+		entry, err := x.Plan(config, preview)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+//Plan same walk as Build but renders templates into memory instead of creating files.
+//It returns nil when the object condition excludes it or its name renders empty
+func (o *Object) Plan(config *Config, preview bool) (*PlanEntry, error) {
+	return o.plan(config, "", preview)
+}
+
+func (o *Object) plan(config *Config, parent string, preview bool) (*PlanEntry, error) {
+	include, err := o.included(config)
+	if err != nil || !include {
+		return nil, err
+	}
+	name, err := o.renderName(config)
+	if err != nil || name == "" {
+		return nil, err
+	}
+	entry := &PlanEntry{
+		Path: path.Join(parent, name),
+		Type: o.Type,
+	}
+	if o.Type == TypeFile {
+		var content []byte
+		action := "rendering"
+		if o.Edit != nil {
+			action = "editing"
+			content, err = o.edit(config.path(entry.Path))
+		} else {
+			content, err = o.render(config)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", action, entry.Path, err)
+		}
+		entry.Size = len(content)
+		entry.Status = fileStatus(config.path(entry.Path), content)
+		if (o.Edit != nil || o.Generated) && entry.Status == PlanStatusConflict {
+			entry.Status = PlanStatusModified
+		}
+		if preview {
+			entry.Content = string(content)
+		}
+	}
+	for _, x := range o.SubObjects {
+		child, err := x.plan(config, entry.Path, preview)
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			entry.Children = append(entry.Children, child)
+		}
+	}
+	return entry, nil
+}
+
+func fileStatus(path string, content []byte) string {
+	existing, err := ioutil.ReadFile(path)
+	if err != nil {
+		return PlanStatusNew
+	}
+	if bytes.Equal(existing, content) {
+		return PlanStatusUnchanged
+	}
+	return PlanStatusConflict
+}
+
+//PrintPlan writes the plan to w as an ascii tree or as json, both nested by path, see nestPlan
+func PrintPlan(w io.Writer, entries []*PlanEntry, format string) error {
+	switch format {
+	case PlanFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(nestPlan(entries))
+	case PlanFormatTree, "":
+		for _, x := range nestPlan(entries) {
+			printEntry(w, x, x.Path, "", "")
+			printTree(w, x.Children, "")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown plan format %s, available: %s, %s", format, PlanFormatTree, PlanFormatJSON)
+}
+
+//nestPlan rebuilds the tree from the path of every entry. Templated names and the copies in ManifestDir span
+//several path segments below the object that plans them, every segment becomes its own directory entry.
+//Entries keep the order they are planned in, directories nobody planned are added where they are first needed
+func nestPlan(entries []*PlanEntry) []*PlanEntry {
+	var roots []*PlanEntry
+	byPath := map[string]*PlanEntry{}
+	var add func(p, typ string) *PlanEntry
+	add = func(p, typ string) *PlanEntry {
+		if x, ok := byPath[p]; ok {
+			return x
+		}
+		x := &PlanEntry{Path: p, Type: typ}
+		byPath[p] = x
+		if parent := path.Dir(p); parent != "." && parent != "/" {
+			dir := add(parent, TypeDir)
+			dir.Children = append(dir.Children, x)
+		} else {
+			roots = append(roots, x)
+		}
+		return x
+	}
+	var walk func(entries []*PlanEntry)
+	walk = func(entries []*PlanEntry) {
+		for _, x := range entries {
+			nested := add(x.Path, x.Type)
+			nested.Type, nested.Size, nested.Status, nested.Content = x.Type, x.Size, x.Status, x.Content
+			walk(x.Children)
+		}
+	}
+	walk(entries)
+	return roots
+}
+
+func printTree(w io.Writer, entries []*PlanEntry, indent string) {
+	for i, x := range entries {
+		branch, childIndent := "|-- ", "|   "
+		if i == len(entries)-1 {
+			branch, childIndent = "`-- ", "    "
+		}
+		printEntry(w, x, path.Base(x.Path), indent+branch, indent+childIndent)
+		printTree(w, x.Children, indent+childIndent)
+	}
+}
+
+//printEntry writes a single tree line for x and its preview lines
+func printEntry(w io.Writer, x *PlanEntry, name, prefix, contentIndent string) {
+	if x.Type == TypeDir {
+		fmt.Fprintf(w, "%s%s/\n", prefix, name)
+	} else {
+		fmt.Fprintf(w, "%s%s (%d B, %s)\n", prefix, name, x.Size, x.Status)
+	}
+	if x.Content != "" {
+		for _, line := range strings.Split(strings.TrimRight(x.Content, "\n"), "\n") {
+			fmt.Fprintf(w, "%s  > %s\n", contentIndent, line)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package wizard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+//planFiles rendered content of every file opts generates, by path relative to the project root
+func planFiles(t *testing.T, opts *Options) map[string]string {
+	opts.Complete()
+	entries, err := Plan(opts, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	var walk func(entries []*PlanEntry)
+	walk = func(entries []*PlanEntry) {
+		for _, x := range entries {
+			if x.Type == TypeFile && !inManifestDir(x.Path) {
+				files[strings.TrimPrefix(x.Path, opts.ProjectName+"/")] = x.Content
+			}
+			walk(x.Children)
+		}
+	}
+	walk(entries)
+	return files
+}
+
+func TestPlanFilesEndWithNewline(t *testing.T) {
+	for _, archetype := range Archetypes() {
+		t.Run(archetype, func(t *testing.T) {
+			opts := &Options{FullName: "example.com/acme/shop", Archetype: archetype, Contributing: true}
+			kind := ArchetypeByName(archetype)
+			if kind.Transports == nil {
+				opts.Transports = Transports()
+			}
+			if kind.Service {
+				opts.Database, opts.Migrations, opts.Modules = postgresDatabase, true, Modules()
+			}
+			for name, content := range planFiles(t, opts) {
+				if content == "" {
+					continue
+				}
+				if !strings.HasSuffix(content, "\n") || strings.HasSuffix(content, "\n\n") {
+					t.Errorf("%s does not end with a single newline: %q", name, content[strings.LastIndex(strings.TrimRight(content, "\n"), "\n")+1:])
+				}
+				if strings.HasPrefix(content, "\n") {
+					t.Errorf("%s starts with an empty line", name)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanEnv(t *testing.T) {
+	opts := NewOptionsFromName("example.com/acme/shop", "chi")
+	files := planFiles(t, opts)
+	want := "HOST=0.0.0.0\nPORT=8080\n"
+	for _, name := range []string{".env", ".env.example"} {
+		if files[name] != want {
+			t.Errorf("%s = %q, want %q", name, files[name], want)
+		}
+	}
+}
+
+//TestPlanHTTPAddr every http framework listens on HOST and PORT of .env
+func TestPlanHTTPAddr(t *testing.T) {
+	for _, framework := range HTTPFrameworks() {
+		t.Run(framework, func(t *testing.T) {
+			files := planFiles(t, NewOptionsFromName("example.com/acme/shop", framework))
+			main := files["cmd/shop/main.go"]
+			if strings.Contains(main, ":8080") || !strings.Contains(main, "serveHTTP(ctx, ") || !strings.Contains(main, "httpAddr()") {
+				t.Errorf("main.go does not serve on httpAddr()\n%s", main)
+			}
+			if serve := files["cmd/shop/serve.go"]; !strings.Contains(serve, `os.Getenv("PORT")`) {
+				t.Errorf("serve.go does not read PORT\n%s", serve)
+			}
+			//the image exposes the port the service reads from PORT
+			if dockerfile := files["docker/Dockerfile"]; !strings.Contains(dockerfile, "ENV PORT=${PORT}\nEXPOSE ${PORT}\n") || strings.Contains(dockerfile, "EXPOSE 8080") {
+				t.Errorf("docker/Dockerfile does not expose PORT\n%s", dockerfile)
+			}
+		})
+	}
+}
+
+func TestPlanLicense(t *testing.T) {
+	for _, license := range Licenses() {
+		t.Run(license, func(t *testing.T) {
+			opts := NewOptionsFromName("example.com/acme/shop", "")
+			opts.License = license
+			opts.Year = 2024
+			text := planFiles(t, opts)["LICENSE"]
+			copyright := strings.Contains(text, "Copyright (c) 2024 acme") || strings.Contains(text, "Copyright 2024 acme")
+			//the MPL is published without a copyright line, the notice goes into the source files
+			if want := license != mplLicense; copyright != want {
+				t.Errorf("LICENSE names the copyright holder: %v, want %v\n%s", copyright, want, text[:200])
+			}
+		})
+	}
+}
+
+//planEntries what a blueprint with a templated multi segment name and the manifest copies plans
+func planEntries() []*PlanEntry {
+	return []*PlanEntry{
+		{Path: "shop", Type: TypeDir, Children: []*PlanEntry{
+			{Path: "shop/cmd/shop", Type: TypeDir, Children: []*PlanEntry{
+				{Path: "shop/cmd/shop/main.go", Type: TypeFile, Size: 13, Status: PlanStatusNew, Content: "package main\n"},
+			}},
+			{Path: "shop/go.mod", Type: TypeFile, Size: 26, Status: PlanStatusUnchanged},
+			{Path: "shop/.stock/cmd/shop/main.go", Type: TypeFile, Size: 13, Status: PlanStatusConflict},
+			{Path: "shop/.stock/go.mod", Type: TypeFile, Size: 26, Status: PlanStatusNew},
+		}},
+		{Path: "README.md", Type: TypeFile, Size: 6, Status: PlanStatusModified},
+	}
+}
+
+func TestPrintPlanTree(t *testing.T) {
+	var out strings.Builder
+	if err := PrintPlan(&out, planEntries(), PlanFormatTree); err != nil {
+		t.Fatal(err)
+	}
+	want := `shop/
+|-- cmd/
+|   ` + "`" + `-- shop/
+|       ` + "`" + `-- main.go (13 B, new)
+|             > package main
+|-- go.mod (26 B, unchanged)
+` + "`" + `-- .stock/
+    |-- cmd/
+    |   ` + "`" + `-- shop/
+    |       ` + "`" + `-- main.go (13 B, conflict)
+    ` + "`" + `-- go.mod (26 B, new)
+README.md (6 B, modified)
+`
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPrintPlanJSON(t *testing.T) {
+	var out strings.Builder
+	if err := PrintPlan(&out, planEntries(), PlanFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var got []*PlanEntry
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	//path and type of every entry, indented by depth
+	var shape []string
+	var walk func(entries []*PlanEntry, indent string)
+	walk = func(entries []*PlanEntry, indent string) {
+		for _, x := range entries {
+			shape = append(shape, indent+x.Path+" "+x.Type)
+			walk(x.Children, indent+"  ")
+		}
+	}
+	walk(got, "")
+	want := []string{
+		"shop dir",
+		"  shop/cmd dir",
+		"    shop/cmd/shop dir",
+		"      shop/cmd/shop/main.go file",
+		"  shop/go.mod file",
+		"  shop/.stock dir",
+		"    shop/.stock/cmd dir",
+		"      shop/.stock/cmd/shop dir",
+		"        shop/.stock/cmd/shop/main.go file",
+		"    shop/.stock/go.mod file",
+		"README.md file",
+	}
+	if strings.Join(shape, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(shape, "\n"), strings.Join(want, "\n"))
+	}
+	if main := got[0].Children[0].Children[0].Children[0]; main.Size != 13 || main.Status != PlanStatusNew || main.Content != "package main\n" {
+		t.Errorf("main.go lost its details: %+v", main)
+	}
+	if err := PrintPlan(&out, planEntries(), "xml"); err == nil || !strings.Contains(err.Error(), "unknown plan format xml") {
+		t.Errorf("error = %v, want the unknown format", err)
+	}
+}
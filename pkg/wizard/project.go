@@ -0,0 +1,88 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/AkronimBlack/stock/common"
+)
+
+var moduleLine = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?\s*$`)
+
+//Project an existing project generated by stock
+type Project struct {
+	Dir        string           `json:"dir"`
+	ModulePath string           `json:"module_path"`
+	Framework  string           `json:"framework"`
+	Database   string           `json:"database"`
+	Names      *common.NameData `json:"names"`
+	//MainFile main.go holding httpRouter() and buildDependencies(), relative to Dir. Empty if there is none
+	MainFile string `json:"main_file"`
+}
+
+//LoadProject reads the module path, the http framework and the database of the project in dir
+func LoadProject(dir string) (*Project, error) {
+	goMod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a go project: %w", dir, err)
+	}
+	m := moduleLine.FindSubmatch(goMod)
+	if m == nil {
+		return nil, fmt.Errorf("no module path in %s", filepath.Join(dir, "go.mod"))
+	}
+	names, err := common.ParseModulePath(string(m[1]))
+	if err != nil {
+		return nil, err
+	}
+	return &Project{
+		Dir:        dir,
+		ModulePath: names.ModulePath,
+		Framework:  detectFramework(goMod),
+		Database:   detectDatabase(goMod),
+		Names:      names,
+		MainFile:   findMainFile(dir, names.BinaryName),
+	}, nil
+}
+
+//findMainFile cmd/{binary}/main.go as generated, otherwise the first cmd/*/main.go or main.go in the project root
+func findMainFile(dir, binaryName string) string {
+	candidates := []string{filepath.Join("cmd", binaryName, "main.go")}
+	if matches, err := filepath.Glob(filepath.Join(dir, "cmd", "*", "main.go")); err == nil {
+		for _, x := range matches {
+			if rel, err := filepath.Rel(dir, x); err == nil {
+				candidates = append(candidates, rel)
+			}
+		}
+	}
+	candidates = append(candidates, "main.go")
+	for _, x := range candidates {
+		if _, err := os.Stat(filepath.Join(dir, x)); err == nil {
+			return filepath.ToSlash(x)
+		}
+	}
+	return ""
+}
+
+//detectFramework finds the registered framework required by go.mod, none when there is no match
+func detectFramework(goMod []byte) string {
+	for _, x := range frameworks {
+		if x.Module != "" && bytes.Contains(goMod, []byte(x.Module+" ")) {
+			return x.Name
+		}
+	}
+	return noFramework
+}
+
+//detectDatabase finds the registered database whose driver go.mod requires, none when there is no match
+func detectDatabase(goMod []byte) string {
+	for _, x := range databases {
+		if x.Module != "" && bytes.Contains(goMod, []byte(x.Module+" ")) {
+			return x.Name
+		}
+	}
+	return noDatabase
+}
@@ -0,0 +1,254 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+//transaction stages rendered objects next to the output directory and moves them into place.
+//Every change made to the output directory is journaled so it can be undone
+type transaction struct {
+	config  *Config
+	staging string
+	journal []func() error
+	//created output directories that did not exist, they are removed after the staging directory on rollback
+	created    []string
+	rolledBack bool
+}
+
+//Generate renders every object, stages and verifies the result and only then commits it to config.OutputDir.
+//Nothing is written when a generated file does not parse, with config.KeepInvalid the files are written anyway
+//and the problems reported once the run is done, see Validate
+func Generate(config *Config, objects []*Object) error {
+	entries, err := PlanObjects(config, objects, true)
+	if err != nil {
+		return err
+	}
+	problems := Validate(config, entries)
+	if len(problems) > 0 && !config.KeepInvalid {
+		return problemsError(problems)
+	}
+
+	tx := &transaction{config: config}
+	defer tx.cleanup()
+	if err = tx.begin(); err != nil {
+		tx.rollback()
+		return err
+	}
+
+	if err = tx.stage(entries); err != nil {
+		tx.rollback()
+		return fmt.Errorf("staging: %w", err)
+	}
+	if err = tx.verify(entries); err != nil {
+		tx.rollback()
+		return fmt.Errorf("verifying staged files: %w", err)
+	}
+	actions, err := tx.resolve(entries)
+	if err != nil {
+		tx.rollback()
+		return err
+	}
+	if err = tx.commit(entries, actions); err != nil {
+		log.Println("Generation failed, rolling back")
+		tx.rollback()
+		return err
+	}
+	if len(problems) > 0 {
+		log.Printf("%d problems in generated files, written anyway:", len(problems))
+		for _, x := range problems {
+			log.Println(x)
+		}
+	}
+	return nil
+}
+
+//begin creates the staging directory inside the output directory so staged files can be renamed into place
+func (t *transaction) begin() error {
+	base := t.config.OutputDir
+	if base == "" {
+		base = "."
+	}
+	if err := t.mkdirAll(base); err != nil {
+		return err
+	}
+	staging, err := ioutil.TempDir(base, ".stock-staging-")
+	if err != nil {
+		return err
+	}
+	t.staging = staging
+	return nil
+}
+
+//mkdirAll creates dir and every missing parent, remembering each of them for rollback
+func (t *transaction) mkdirAll(dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if err := t.mkdirAll(filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, os.ModePerm); err != nil {
+		return err
+	}
+	t.created = append(t.created, dir)
+	return nil
+}
+
+func (t *transaction) staged(entry *PlanEntry) string {
+	return filepath.Join(t.staging, entry.Path)
+}
+
+func (t *transaction) stage(entries []*PlanEntry) error {
+	for _, entry := range entries {
+		var err error
+		switch entry.Type {
+		case TypeDir:
+			err = os.MkdirAll(t.staged(entry), os.ModePerm)
+		case TypeFile:
+			//templated names can nest a file in directories that are not objects themselves
+			if err = os.MkdirAll(filepath.Dir(t.staged(entry)), os.ModePerm); err == nil {
+				err = ioutil.WriteFile(t.staged(entry), []byte(entry.Content), 0644)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if err = t.stage(entry.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//verify checks that every planned file made it to the staging directory with the rendered content
+func (t *transaction) verify(entries []*PlanEntry) error {
+	for _, entry := range entries {
+		if entry.Type == TypeFile {
+			content, err := ioutil.ReadFile(t.staged(entry))
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(content, []byte(entry.Content)) {
+				return fmt.Errorf("%s was not staged completely", entry.Path)
+			}
+		}
+		if err := t.verify(entry.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//resolve applies the conflict policy to every file before anything in the output directory changes
+func (t *transaction) resolve(entries []*PlanEntry) (map[string]string, error) {
+	actions := map[string]string{}
+	var walk func(entries []*PlanEntry) error
+	walk = func(entries []*PlanEntry) error {
+		for _, entry := range entries {
+			if entry.Status == PlanStatusModified {
+				actions[entry.Path] = actionOverwrite
+			} else if entry.Type == TypeFile {
+				action, err := resolveConflict(t.config.path(entry.Path), []byte(entry.Content), t.config.ConflictPolicy)
+				if err != nil {
+					return err
+				}
+				actions[entry.Path] = action
+			}
+			if err := walk(entry.Children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return actions, walk(entries)
+}
+
+func (t *transaction) commit(entries []*PlanEntry, actions map[string]string) error {
+	for _, entry := range entries {
+		target := t.config.path(entry.Path)
+		if err := t.mkdirAll(filepath.Dir(target)); err != nil {
+			return err
+		}
+		if entry.Type == TypeDir {
+			if _, err := os.Stat(target); os.IsNotExist(err) {
+				//a directory that does not exist yet is moved in one atomic rename with everything inside it
+				log.Println("Creating", target)
+				if err = t.rename(t.staged(entry), target); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := t.commit(entry.Children, actions); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch actions[entry.Path] {
+		case actionKeep:
+			continue
+		case actionBackup:
+			backup := backupPath(target)
+			log.Printf("Backing up %s to %s", target, backup)
+			if err := t.rename(target, backup); err != nil {
+				return err
+			}
+		case actionOverwrite:
+			//keep the original in staging so a rollback can restore it
+			if err := t.rename(target, t.staged(entry)+".orig"); err != nil {
+				return err
+			}
+		}
+		if entry.Status == PlanStatusModified {
+			log.Println("Updating", target)
+		} else {
+			log.Println("Creating", target)
+		}
+		if err := t.rename(t.staged(entry), target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *transaction) rename(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	t.journal = append(t.journal, func() error { return os.Rename(to, from) })
+	return nil
+}
+
+//rollback undoes every journaled change in reverse order
+func (t *transaction) rollback() {
+	for i := len(t.journal) - 1; i >= 0; i-- {
+		if err := t.journal[i](); err != nil {
+			log.Println("Rollback:", err)
+		}
+	}
+	t.journal = nil
+	t.rolledBack = true
+}
+
+//cleanup removes the staging directory and, after a rollback, the output directories the transaction created.
+//begin creates them before the staging directory, they are removed even when there is no staging directory
+func (t *transaction) cleanup() {
+	if t.staging != "" {
+		if err := os.RemoveAll(t.staging); err != nil {
+			log.Println("Removing staging directory:", err)
+		}
+	}
+	if !t.rolledBack {
+		return
+	}
+	for i := len(t.created) - 1; i >= 0; i-- {
+		if err := os.Remove(t.created[i]); err != nil {
+			log.Println("Rollback:", err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package wizard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//snapshot every directory and the content of every file below root, by path relative to root
+func snapshot(t *testing.T, root string) map[string]string {
+	tree := map[string]string{}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			tree[name+"/"] = ""
+			return nil
+		}
+		b, err := ioutil.ReadFile(p)
+		tree[name] = string(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func file(name, content string) *Object {
+	return &Object{Name: name, Type: TypeFile, Content: []byte(content)}
+}
+
+func dir(name string, children ...*Object) *Object {
+	return &Object{Name: name, Type: TypeDir, SubObjects: children}
+}
+
+//TestGenerateRollback fails generation at every step and checks that the output directory is left byte for byte as it was
+func TestGenerateRollback(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		policy  string
+		objects []*Object
+		wantErr string
+	}{
+		{
+			name:    "staging",
+			output:  "out",
+			objects: []*Object{dir("shop", file("a.txt", "new a\n")), file("f", "f\n"), file("f/g", "g\n")},
+			wantErr: "staging: ",
+		},
+		{
+			name:    "staging in a new output directory",
+			output:  "missing/out",
+			objects: []*Object{file("f", "f\n"), file("f/g", "g\n")},
+			wantErr: "staging: ",
+		},
+		{
+			//the second file overwrites the first one in staging
+			name:    "verify",
+			output:  "missing/out",
+			objects: []*Object{file("twice.txt", "first\n"), file("twice.txt", "second\n")},
+			wantErr: "verifying staged files: twice.txt was not staged completely",
+		},
+		{
+			name:    "conflict",
+			output:  "out",
+			policy:  ConflictFail,
+			objects: []*Object{file("new.txt", "new\n"), dir("shop", file("a.txt", "new a\n"))},
+			wantErr: "already exists and differs",
+		},
+		{
+			//shop/x is a file, the directory below it cannot be created once a.txt, b.txt and newdir are in place
+			name:   "commit",
+			output: "out",
+			policy: ConflictOverwrite,
+			objects: []*Object{
+				file("new.txt", "new\n"),
+				dir("shop",
+					file("a.txt", "new a\n"),
+					dir("newdir", file("n.txt", "n\n")),
+					file("b.txt", "b\n"),
+					dir("x/sub/deeper"),
+				),
+			},
+			wantErr: "not a directory",
+		},
+		{
+			name:   "backup",
+			output: "out",
+			policy: ConflictBackup,
+			objects: []*Object{
+				dir("shop", file("a.txt", "new a\n"), dir("x/sub/deeper")),
+			},
+			wantErr: "not a directory",
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(root, "out", "shop"), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, filepath.Join(root, "out", "shop", "a.txt"), "old a\n")
+			writeFile(t, filepath.Join(root, "out", "shop", "x"), "x\n")
+			before := snapshot(t, root)
+
+			config := NewConfig("shop", "example.com/acme/shop", "acme", nil)
+			config.OutputDir = filepath.Join(root, x.output)
+			config.ConflictPolicy = x.policy
+			err := Generate(config, x.objects)
+			if err == nil || !strings.Contains(err.Error(), x.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, x.wantErr)
+			}
+			if after := snapshot(t, root); !reflect.DeepEqual(after, before) {
+				t.Errorf("the output directory changed\ngot  %q\nwant %q", after, before)
+			}
+		})
+	}
+}
+
+//TestCleanupWithoutStaging directories created before the staging directory could be created are removed as well
+func TestCleanupWithoutStaging(t *testing.T) {
+	root := t.TempDir()
+	tx := &transaction{config: &Config{OutputDir: filepath.Join(root, "a", "b")}}
+	if err := tx.mkdirAll(tx.config.OutputDir); err != nil {
+		t.Fatal(err)
+	}
+	tx.rollback()
+	tx.cleanup()
+	if got := snapshot(t, root); !reflect.DeepEqual(got, map[string]string{"./": ""}) {
+		t.Errorf("created directories are left behind: %q", got)
+	}
+}
@@ -0,0 +1,37 @@
+package wizard
+
+const (
+	transportHTTP = "http"
+	transportGRPC = "grpc"
+	transportAMQP = "amqp"
+)
+
+//Transports list of ways a generated service can be reached
+func Transports() []string {
+	return []string{transportHTTP, transportGRPC, transportAMQP}
+}
+
+//DefaultTransports transports selected when none are given
+func DefaultTransports() []string {
+	return []string{transportHTTP}
+}
+
+func isTransport(name string) bool {
+	for _, x := range Transports() {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
+
+//HasTransport reports if the transport was selected, used by templates and blueprint conditions
+//eg. when: .HasTransport "grpc"
+func (o *Options) HasTransport(name string) bool {
+	for _, x := range o.Transports {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package wizard
+
+import (
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+	"testing"
+)
+
+//parseGoFiles fails the test for every generated go file that does not parse
+func parseGoFiles(t *testing.T, files map[string]string) {
+	for name, content := range files {
+		if path.Ext(name) != ".go" {
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), name, content, parser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", name, err)
+		}
+	}
+}
+
+//TestAMQPTransport the amqp transport generates the client, serves the consumer next to the other transports
+//and configures the broker, for every framework and the worker archetype
+func TestAMQPTransport(t *testing.T) {
+	var projects []*Options
+	for _, framework := range HTTPFrameworks() {
+		for _, transports := range [][]string{{transportHTTP}, {transportHTTP, transportAMQP}} {
+			opts := NewOptionsFromName("example.com/acme/shop", framework)
+			opts.Transports = transports
+			projects = append(projects, opts)
+		}
+	}
+	projects = append(projects, &Options{FullName: "example.com/acme/shop", Archetype: workerArchetype})
+	for _, opts := range projects {
+		opts.Complete()
+		t.Run(opts.Archetype+"/"+opts.Framework+"/"+strings.Join(opts.Transports, ","), func(t *testing.T) {
+			files := planFiles(t, opts)
+			parseGoFiles(t, files)
+			amqp := opts.HasTransport(transportAMQP)
+			for _, name := range []string{"connection.go", "consumer.go", "publisher.go"} {
+				if _, ok := files["infrastructure/transport/amqp/"+name]; ok != amqp {
+					t.Errorf("infrastructure/transport/amqp/%s generated: %v, want %v", name, ok, amqp)
+				}
+			}
+			contains := map[string][]string{
+				"cmd/shop/main.go":   {"amqptransport.Serve(ctx, amqpConsumer(), amqpPublisher())", `consumer.Handle("shop.events", amqptransport.LogMessage)`},
+				".env":               {"AMQP_HOST=127.0.0.1\n", "AMQP_PORT=5672\n", "AMQP_USERNAME=", "AMQP_PASSWORD="},
+				"docker-compose.yml": {"shop_broker:", "AMQP_HOST: shop_broker"},
+				"go.mod":             {"github.com/Azure/go-amqp "},
+			}
+			for name, parts := range contains {
+				for _, x := range parts {
+					if got := strings.Contains(files[name], x); got != amqp {
+						t.Errorf("%s contains %q: %v, want %v", name, x, got, amqp)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package wizard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v2"
+)
+
+//Problem a generated file that does not parse, Line is 0 when the problem is not tied to a line
+type Problem struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p *Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Message)
+}
+
+//problemsError every problem on a line of its own
+func problemsError(problems []*Problem) error {
+	lines := make([]string, 0, len(problems))
+	for _, x := range problems {
+		lines = append(lines, x.String())
+	}
+	return fmt.Errorf("%d problems in generated files, nothing was written:\n%s", len(problems), strings.Join(lines, "\n"))
+}
+
+//yamlLine line number of yaml errors, eg. "yaml: line 3: mapping values are not allowed in this context"
+var yamlLine = regexp.MustCompile(`line (\d+): `)
+
+//Validate checks every rendered file of the plan: go files are parsed and gofmt'd in place, go.mod is parsed
+//with the modfile parser and yaml files (docker-compose.yml, buf.yaml) with the yaml parser. The copies kept in
+//ManifestDir are the same files and are skipped.
+//Entries need their content, see PlanObjects with preview
+func Validate(config *Config, entries []*PlanEntry) []*Problem {
+	var problems []*Problem
+	for _, x := range entries {
+		if x.Type == TypeFile && !inManifestDir(x.Path) {
+			problems = append(problems, x.validate(config.path(x.Path))...)
+		}
+		problems = append(problems, Validate(config, x.Children)...)
+	}
+	return problems
+}
+
+//validate checks the content of a file entry, filename is the path problems are reported for
+func (e *PlanEntry) validate(filename string) []*Problem {
+	content := []byte(e.Content)
+	switch name := path.Base(e.Path); {
+	case path.Ext(name) == ".go":
+		if _, err := parser.ParseFile(token.NewFileSet(), filename, content, 0); err != nil {
+			return goProblems(filename, err)
+		}
+		formatted, err := format.Source(content)
+		if err != nil {
+			return []*Problem{{Path: filename, Message: err.Error()}}
+		}
+		if !bytes.Equal(formatted, content) {
+			e.Content = string(formatted)
+			e.Size = len(formatted)
+			//edited and regenerated files stay modified, see Object.plan
+			if status := fileStatus(filename, formatted); e.Status != PlanStatusModified || status == PlanStatusUnchanged {
+				e.Status = status
+			}
+		}
+	case name == "go.mod":
+		if _, err := modfile.Parse(filename, content, nil); err != nil {
+			return modProblems(filename, err)
+		}
+	case path.Ext(name) == ".yml" || path.Ext(name) == ".yaml":
+		var document interface{}
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			problem := &Problem{Path: filename, Message: err.Error()}
+			if match := yamlLine.FindStringSubmatch(err.Error()); match != nil {
+				problem.Line, _ = strconv.Atoi(match[1])
+			}
+			return []*Problem{problem}
+		}
+	}
+	return nil
+}
+
+func goProblems(filename string, err error) []*Problem {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return []*Problem{{Path: filename, Message: err.Error()}}
+	}
+	problems := make([]*Problem, 0, len(list))
+	for _, x := range list {
+		problems = append(problems, &Problem{Path: filename, Line: x.Pos.Line, Message: x.Msg})
+	}
+	return problems
+}
+
+func modProblems(filename string, err error) []*Problem {
+	var list modfile.ErrorList
+	if !errors.As(err, &list) {
+		return []*Problem{{Path: filename, Message: err.Error()}}
+	}
+	problems := make([]*Problem, 0, len(list))
+	for _, x := range list {
+		problems = append(problems, &Problem{Path: filename, Line: x.Pos.Line, Message: x.Err.Error()})
+	}
+	return problems
+}
@@ -0,0 +1,112 @@
+package wizard
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []string
+		//formatted content of a go file that parses
+		formatted string
+	}{
+		{
+			name:    "go syntax errors",
+			path:    "shop/main.go",
+			content: "package main\n\nfunc main() {\n\tx := \n}\n\nvar y = )\n",
+			want: []string{
+				"out/shop/main.go:5: expected operand, found '}'",
+				"out/shop/main.go:7: expected ';', found 'var'",
+			},
+		},
+		{
+			name:    "go without package clause",
+			path:    "shop/doc.go",
+			content: "//Package shop\n",
+			want:    []string{"out/shop/doc.go:1: expected 'package', found 'EOF'"},
+		},
+		{
+			name:      "go formatted",
+			path:      "shop/main.go",
+			content:   "package main\nfunc main(){\nprintln( 1 )\n}\n",
+			formatted: "package main\n\nfunc main() {\n\tprintln(1)\n}\n",
+		},
+		{
+			name:    "go.mod",
+			path:    "shop/go.mod",
+			content: "module example.com/acme/shop\n\ngo 1.16\n\nrequire github.com/lib/pq\n",
+			want:    []string{"out/shop/go.mod:5: usage: require module/path v1.2.3"},
+		},
+		{
+			name:    "yaml",
+			path:    "shop/docker-compose.yml",
+			content: "services:\n  app:\n    image: golang\n   ports: [8080]\n",
+			//reported on the line of the mapping the wrongly indented key breaks
+			want: []string{"out/shop/docker-compose.yml:3: yaml: line 3: did not find expected key"},
+		},
+		{
+			name:    "not validated",
+			path:    "shop/README.md",
+			content: "package main\n\nfunc {\n",
+		},
+		{
+			name:    "copies in the manifest directory",
+			path:    "shop/" + ManifestDir + "/main.go",
+			content: "package main\n\nfunc {\n",
+		},
+	}
+	for _, x := range tests {
+		t.Run(x.name, func(t *testing.T) {
+			config := &Config{OutputDir: "out"}
+			entry := &PlanEntry{Path: x.path, Type: TypeFile, Status: PlanStatusNew, Content: x.content}
+			//problems are found below directories as well
+			entries := []*PlanEntry{{Path: "shop", Type: TypeDir, Children: []*PlanEntry{entry}}}
+			var got []string
+			for _, p := range Validate(config, entries) {
+				got = append(got, filepath.ToSlash(p.String()))
+			}
+			if !reflect.DeepEqual(got, x.want) {
+				t.Errorf("problems\n%q\nwant\n%q", got, x.want)
+			}
+			want := x.content
+			if x.formatted != "" {
+				want = x.formatted
+			}
+			if entry.Content != want || entry.Size != 0 && entry.Size != len(want) {
+				t.Errorf("content %q (%d bytes), want %q", entry.Content, entry.Size, want)
+			}
+		})
+	}
+}
+
+//TestGenerateInvalid a go file that does not parse fails generation before anything is written,
+//KeepInvalid writes it anyway
+func TestGenerateInvalid(t *testing.T) {
+	objects := []*Object{dir("shop", file("valid.go", "package shop\n"), file("invalid.go", "package shop\n\nfunc {\n"))}
+
+	root := t.TempDir()
+	config := NewConfig("shop", "example.com/acme/shop", "acme", nil)
+	config.OutputDir = root
+	err := Generate(config, objects)
+	want := "1 problems in generated files, nothing was written:\n" + filepath.Join(root, "shop", "invalid.go") + ":3: expected 'IDENT', found '{'"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %s", err, want)
+	}
+	if got := snapshot(t, root); !reflect.DeepEqual(got, map[string]string{"./": ""}) {
+		t.Errorf("files were written: %q", got)
+	}
+
+	config.KeepInvalid = true
+	if err = Generate(config, objects); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(root, "shop", "invalid.go")); !strings.HasSuffix(got, "func {\n") {
+		t.Errorf("invalid.go = %q", got)
+	}
+}